@@ -0,0 +1,143 @@
+package datemath_parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// HolidayCalendar decides business days for unit `bd`, date is midnight in time zone of parser.
+type HolidayCalendar interface {
+	IsBusinessDay(date time.Time) bool
+}
+
+// WeekendCalendar takes days except saturday and sunday as business days, it's the default calendar.
+type WeekendCalendar struct{}
+
+func (WeekendCalendar) IsBusinessDay(date time.Time) bool {
+	return date.Weekday() != time.Saturday && date.Weekday() != time.Sunday
+}
+
+// HolidayList is weekend calendar with holidays.
+type HolidayList struct {
+	holidays map[string]bool // dates like `2021-12-25`
+	yearly   []yearlyHoliday
+	skipped  []string // events of ics file with unsupported recurrence
+}
+
+// NewHolidayList creates calendar whose holidays are dates of given times.
+func NewHolidayList(dates ...time.Time) *HolidayList {
+	var h = &HolidayList{holidays: map[string]bool{}}
+	for _, date := range dates {
+		h.Add(date)
+	}
+	return h
+}
+
+// Add adds date of tim as holiday.
+func (h *HolidayList) Add(tim time.Time) {
+	h.holidays[tim.Format("2006-01-02")] = true
+}
+
+func (h *HolidayList) IsBusinessDay(date time.Time) bool {
+	if !(WeekendCalendar{}).IsBusinessDay(date) || h.holidays[date.Format("2006-01-02")] {
+		return false
+	}
+	for _, y := range h.yearly {
+		if y.contains(date) {
+			return false
+		}
+	}
+	return true
+}
+
+// LoadHolidayList reads holidays from lines like `2021-12-25 Christmas Day`, the date is
+// followed by optional name, blank lines and lines starting with `#` are skipped.
+func LoadHolidayList(r io.Reader) (*HolidayList, error) {
+	var h = NewHolidayList()
+	var scanner = bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		var text = strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+		var fields = strings.Fields(text)
+		var date, err = time.Parse("2006-01-02", fields[0])
+		if err != nil {
+			return nil, fmt.Errorf("holiday list: line %d is invalid, expect date like 2021-12-25: %s", line, text)
+		}
+		h.Add(date)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return h, nil
+}
+
+// maxBusinessDays limits amount of `bd`, which steps day by day, it's about 380 years.
+const maxBusinessDays = 100000
+
+// maxNonBusinessDays limits search of business day, calendar without business day in a year is broken.
+const maxNonBusinessDays = 366
+
+// calendar returns calendar of parser, which is WeekendCalendar by default.
+func (p *DateMathParser) calendar() HolidayCalendar {
+	if p.Calendar == nil {
+		return WeekendCalendar{}
+	}
+	return p.Calendar
+}
+
+// isBusinessDay reports whether the day containing tim in time zone of parser is business day.
+func (p *DateMathParser) isBusinessDay(tim time.Time) bool {
+	return p.calendar().IsBusinessDay(p.floorUnit(tim, "d"))
+}
+
+// addBusinessDays moves tim by amount of business days and keeps time of day,
+// so `now-1bd` on saturday or monday is friday.
+func (p *DateMathParser) addBusinessDays(tim time.Time, op MathOp) (time.Time, error) {
+	if op.Amount > maxBusinessDays {
+		return emptyTime, &RangeError{Op: op.String(), Reason: fmt.Sprintf("amount exceeds %d business days", maxBusinessDays)}
+	}
+	// every business day moves at least one day, less an hour of daylight saving change,
+	// so amount beyond MaxOffset is rejected before stepping
+	if p.MaxOffset > 0 && int64(op.Amount)*24*3600-3600 > int64(p.MaxOffset/time.Second) {
+		return emptyTime, &RangeError{Op: op.String(), Reason: fmt.Sprintf("offset from anchor exceeds %s", p.MaxOffset)}
+	}
+	var step = 1
+	if op.Op == '-' {
+		step = -1
+	}
+	var res = tim.In(p.location())
+	var year, month, day = res.Date()
+	for moved, skipped := 0, 0; moved < op.Amount; {
+		day += step
+		var next = time.Date(year, month, day, res.Hour(), res.Minute(), res.Second(), res.Nanosecond(), p.location())
+		if p.isBusinessDay(next) {
+			moved++
+			skipped = 0
+		} else if skipped++; skipped > maxNonBusinessDays {
+			return emptyTime, &RangeError{Op: op.String(), Reason: "no business day is found by calendar"}
+		}
+		if moved == op.Amount {
+			res = next
+		}
+	}
+	return res.In(tim.Location()), nil
+}
+
+// floorBusinessDay returns start of the last business day not later than tim.
+func (p *DateMathParser) floorBusinessDay(tim time.Time) (time.Time, error) {
+	var res = p.floorUnit(tim, "d")
+	var year, month, day = res.Date()
+	for i := 0; !p.calendar().IsBusinessDay(res); i++ {
+		if i == maxNonBusinessDays {
+			return emptyTime, &RangeError{Op: "/bd", Reason: "no business day is found by calendar"}
+		}
+		day--
+		res = time.Date(year, month, day, 0, 0, 0, 0, p.location())
+	}
+	return res, nil
+}
@@ -0,0 +1,94 @@
+package datemath_parser
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadHolidayList(t *testing.T) {
+	var h, err = LoadHolidayList(strings.NewReader(`
+# holidays of 2021
+2021-12-24 Christmas Eve
+2021-12-31
+`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		date time.Time
+		want bool
+	}{
+		{date: time.Date(2021, 12, 23, 0, 0, 0, 0, time.UTC), want: true},
+		{date: time.Date(2021, 12, 24, 0, 0, 0, 0, time.UTC), want: false},
+		{date: time.Date(2021, 12, 25, 0, 0, 0, 0, time.UTC), want: false},
+		{date: time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC), want: false},
+		{date: time.Date(2022, 1, 3, 0, 0, 0, 0, time.UTC), want: true},
+	}
+	for _, tt := range tests {
+		if got := h.IsBusinessDay(tt.date); got != tt.want {
+			t.Errorf("IsBusinessDay(%v) = %v, want %v", tt.date, got, tt.want)
+		}
+	}
+	if _, err := LoadHolidayList(strings.NewReader("2021/12/24")); err == nil {
+		t.Errorf("expect error for invalid line")
+	}
+}
+
+type noBusinessDay struct{}
+
+func (noBusinessDay) IsBusinessDay(time.Time) bool { return false }
+
+func TestDateMathParser_businessDay(t *testing.T) {
+	// 2021-12-27 is monday
+	var now = time.Date(2021, 12, 27, 10, 0, 0, 0, time.UTC)
+	var holidays = NewHolidayList(time.Date(2021, 12, 24, 0, 0, 0, 0, time.UTC), time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC))
+	tests := []struct {
+		name      string
+		opts      []DateMathParserOption
+		expr      string
+		want      time.Time
+		wantRange bool
+	}{
+		{name: "TestDateMathParser_businessDay01", expr: "now-1bd", want: time.Date(2021, 12, 24, 10, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_businessDay02", expr: "now-5bd", want: time.Date(2021, 12, 20, 10, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_businessDay03", expr: "now+5bd", want: time.Date(2022, 1, 3, 10, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_businessDay04", opts: []DateMathParserOption{WithCalendar(holidays)}, expr: "now-1bd", want: time.Date(2021, 12, 23, 10, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_businessDay05", opts: []DateMathParserOption{WithCalendar(holidays)}, expr: "now+5bd", want: time.Date(2022, 1, 4, 10, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_businessDay06", expr: "now/bd", want: time.Date(2021, 12, 27, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_businessDay07", expr: "now-1d/bd", want: time.Date(2021, 12, 24, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_businessDay08", opts: []DateMathParserOption{WithCalendar(holidays)}, expr: "now-1d/bd", want: time.Date(2021, 12, 23, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_businessDay09", opts: []DateMathParserOption{WithRoundUp(true)}, expr: "now-1d/bd", want: time.Date(2021, 12, 24, 23, 59, 59, 999000000, time.UTC)},
+		{name: "TestDateMathParser_businessDay10", opts: []DateMathParserOption{WithTimeZone("-08:00")}, expr: "now-1bd/bd", want: time.Date(2021, 12, 24, 8, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_businessDay11", expr: "now+0bd", want: now},
+		{name: "TestDateMathParser_businessDay12", expr: "now-99999999999bd", wantRange: true},
+		{name: "TestDateMathParser_businessDay13", opts: []DateMathParserOption{WithCalendar(noBusinessDay{})}, expr: "now/bd", wantRange: true},
+		{name: "TestDateMathParser_businessDay14", expr: "now+9999999bd", wantRange: true},
+		{name: "TestDateMathParser_businessDay15", opts: []DateMathParserOption{WithMaxOffset(24 * time.Hour)}, expr: "now+99999bd", wantRange: true},
+		{name: "TestDateMathParser_businessDay16", opts: []DateMathParserOption{WithMaxOffset(72 * time.Hour)}, expr: "now-1bd", want: time.Date(2021, 12, 24, 10, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_businessDay17", opts: []DateMathParserOption{WithMaxOffset(48 * time.Hour)}, expr: "now-1bd", wantRange: true},
+		{name: "TestDateMathParser_businessDay18", opts: []DateMathParserOption{WithCalendar(noBusinessDay{})}, expr: "now+1bd", wantRange: true},
+		// 100000 business days are 20000 weeks
+		{name: "TestDateMathParser_businessDay19", expr: "now+100000bd", want: time.Date(2405, 4, 18, 10, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts = append([]DateMathParserOption{WithNow(func() time.Time { return now })}, tt.opts...)
+			var p, err = NewDateMathParser(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got, perr = p.Parse(tt.expr)
+			if _, ok := perr.(*RangeError); ok != tt.wantRange {
+				t.Errorf("Parse() error = %v, want range error %v", perr, tt.wantRange)
+				return
+			}
+			if !tt.wantRange && (perr != nil || !got.Equal(tt.want)) {
+				t.Errorf("Parse() = %v, %v, want %v", got, perr, tt.want)
+			}
+		})
+	}
+	if _, err := NewDateMathParser(WithCalendar(nil)); err == nil {
+		t.Errorf("expect error for nil calendar")
+	}
+}
@@ -2,16 +2,14 @@ package datemath_parser
 
 import (
 	"fmt"
+	"math"
 	"regexp"
 	"strconv"
 	"strings"
 	"time"
-
-	"github.com/araddon/dateparse"
-	"github.com/vjeantet/jodaTime"
 )
 
-var durRegexp = regexp.MustCompile(`([\+-]\d*|\/)(y|M|w|d|h|H|m|s)`)
+var durRegexp = regexp.MustCompile(`([\+-]\d*|\/\d*)(bd|fy|fQ|y|M|w|d|h|H|m|s)`)
 
 var emptyTime = time.Unix(0, 0)
 
@@ -24,11 +22,110 @@ var units = map[string]time.Duration{
 	"H": time.Hour,
 	"m": time.Minute,
 	"s": time.Second,
+
+	// fiscal units are added like `y` and `3M`, they differ only in rounding
+	"fy": time.Hour * 365 * 24,
+	"fQ": time.Hour * 90 * 24,
 }
 
 type DateMathParser struct {
 	Formats  []string
 	TimeZone *time.Location
+	// RoundUp makes rounding and partial anchor like `2021-05` resolve to the end
+	// of the unit instead of the start, which is used for `lte` and `gt` bound.
+	RoundUp bool
+	// PatternDialect is the syntax of patterns in Formats without dialect prefix,
+	// patterns of built-in formats are always joda syntax.
+	PatternDialect PatternDialect
+	// LenientFraction makes fraction of second take 1~9 digits in every pattern,
+	// java syntax takes exactly count of S digits without it.
+	LenientFraction bool
+	// Locale is language tag like `de-DE`, which gives names of month and day of week, default is english.
+	Locale string
+	// YearInference decides year of value parsed by pattern without year, default is YearInferenceClosest.
+	YearInference YearInference
+	// TwoDigitYearPivot is the first year of 100 years which two digit year like `yy` maps into,
+	// such as 1950 maps 50~99 into 1950~1999 and 00~49 into 2000~2049. It's default of dialect when it's 0,
+	// java maps into 2000~2099, joda and strftime map into 1969~2068.
+	TwoDigitYearPivot int
+	// Strict makes parsing fail when no format is given, instead of guessing format by dateparse.
+	Strict bool
+	// DayFirst makes ambiguous numeric date like `01/02/2021` guessed by dateparse read as day first.
+	DayFirst bool
+	// Calendar decides business days for unit `bd`, default is WeekendCalendar.
+	Calendar HolidayCalendar
+	// MaxOffset limits how far math operations move time from the anchor, 0 means no limit.
+	MaxOffset time.Duration
+	// FiscalYearStartMonth is the first month of fiscal year for units `fy` and `fQ`, 0 means january.
+	FiscalYearStartMonth time.Month
+	// Dialect is the syntax of date math expression, default is DialectElasticSearch.
+	Dialect Dialect
+	// RoundOffset shifts boundaries of rounding with count like `/15m`, as offset of ElasticSearch date_histogram.
+	RoundOffset time.Duration
+	// Now is clock of parser, which gives `now` of expression and is used by YearInference, default is time.Now.
+	Now func() time.Time
+
+	// weekStart is the first day of week set by WithWeekStart, otherwise weeks start on the first day of
+	// Locale when it's set or weekStartFromLocale is set.
+	weekStart           *time.Weekday
+	weekStartFromLocale bool
+	// twoDigitYearWindow makes pivot of two digit year the given years before year of now, when twoDigitYearSliding is set.
+	twoDigitYearWindow  int
+	twoDigitYearSliding bool
+	// registry resolves format names given to WithFormat, BuiltInFormat is used when it's nil.
+	registry *FormatRegistry
+	// requestedFormats holds formats given to WithFormat, which are expanded after all options are applied.
+	requestedFormats []string
+	// formatSources holds the name given to WithFormat for each entry of Formats,
+	// a format name is expanded to several patterns which all share the name.
+	formatSources []formatSource
+	// patterns caches compiled Formats, epoch formats are nil, patternFormats are the Formats they're compiled from.
+	patterns       []*pattern
+	patternFormats []string
+}
+
+type formatSource struct {
+	name    string
+	builtIn bool // patterns of built-in format are joda syntax
+	options patternOptions
+}
+
+// MathOp is one operation of a date math expression, such as `+1d` or `/h`.
+type MathOp struct {
+	Op     byte // '+', '-' or '/'
+	Amount int  // count of units, 0 when Op is '/' without count like `/d`
+	Unit   string
+}
+
+func (o MathOp) String() string {
+	if o.Op == '/' && o.Amount == 0 {
+		return "/" + o.Unit
+	}
+	return string(o.Op) + strconv.Itoa(o.Amount) + o.Unit
+}
+
+// RangeError reports that math operation is out of range, such as overflow of amount,
+// year out of range or offset exceeding MaxOffset of parser.
+type RangeError struct {
+	Op     string // the math operation like `+999999999y`
+	Reason string
+}
+
+func (e *RangeError) Error() string {
+	return fmt.Sprintf("math: %s is out of range, %s", e.Op, e.Reason)
+}
+
+// Result is the detailed outcome of parsing a date math expression.
+type Result struct {
+	Time      time.Time // the evaluated time in utc
+	Anchor    time.Time // the anchor time before applying Ops in utc, rounded up in round up mode
+	Format    string    // the matched format name or pattern, empty when anchor is now
+	IsNow     bool
+	Ops       []MathOp
+	Precision Precision // the finest field present in the anchor literal
+	// Ambiguous reports that the anchor is guessed by dateparse, and it's another valid date
+	// with day and month swapped, like `01/02/2021`.
+	Ambiguous bool
 }
 
 func NewDateMathParser(opts ...DateMathParserOption) (*DateMathParser, error) {
@@ -40,94 +137,414 @@ func NewDateMathParser(opts ...DateMathParserOption) (*DateMathParser, error) {
 			return nil, err
 		}
 	}
+	if p.requestedFormats != nil {
+		p.expandFormats()
+	}
+	if err := p.compileFormats(); err != nil {
+		return nil, err
+	}
 	return p, nil
 }
 
+// expandFormats expands format names given to WithFormat into Formats.
+func (p *DateMathParser) expandFormats() {
+	p.Formats = []string{}
+	p.formatSources = []formatSource{}
+	for _, format := range p.requestedFormats {
+		if f, ok := p.lookupFormat(format); ok {
+			p.Formats = append(p.Formats, f.patterns...)
+			for range f.patterns {
+				p.formatSources = append(p.formatSources, formatSource{name: format, builtIn: f.builtIn, options: f.options})
+			}
+		} else {
+			p.Formats = append(p.Formats, format)
+			p.formatSources = append(p.formatSources, formatSource{name: format})
+		}
+	}
+}
+
+func (p *DateMathParser) lookupFormat(name string) (registeredFormat, bool) {
+	if p.registry != nil {
+		return p.registry.lookup(name)
+	}
+	var patterns, ok = BuiltInFormat[name]
+	return registeredFormat{patterns: patterns, builtIn: true, options: builtInOptions(name)}, ok
+}
+
+// compileFormats compiles Formats after all options are applied, since dialect may be given after formats.
+func (p *DateMathParser) compileFormats() error {
+	p.patterns = make([]*pattern, len(p.Formats))
+	p.patternFormats = append([]string{}, p.Formats...)
+	for i := range p.Formats {
+		if pat, err := p.compileFormat(i); err != nil {
+			return err
+		} else {
+			p.patterns[i] = pat
+		}
+	}
+	return nil
+}
+
+func (p *DateMathParser) compileFormat(i int) (*pattern, error) {
+	var format = p.Formats[i]
+	if format == EPOCH_SECOND || format == EPOCH_MILLIS || format == EPOCH_AUTO {
+		return nil, nil
+	}
+	var dialect = p.PatternDialect
+	var options = patternOptions{}
+	if i < len(p.formatSources) && p.formatSources[i].builtIn {
+		dialect = PatternJoda
+		options = p.formatSources[i].options
+	}
+	format, dialect = splitPatternDialect(format, dialect)
+	var pat, err = compilePattern(format, dialect)
+	if err != nil {
+		return nil, err
+	}
+	pat.options = options
+	return pat, nil
+}
+
+// pattern returns compiled i-th format, Formats may be assigned without NewDateMathParser or changed later.
+func (p *DateMathParser) pattern(i int) (*pattern, error) {
+	if i < len(p.patterns) && p.patterns[i] != nil && p.patternFormats[i] == p.Formats[i] {
+		return p.patterns[i], nil
+	}
+	return p.compileFormat(i)
+}
+
 func (p *DateMathParser) Parse(expr string) (time.Time, error) {
-	var res time.Time
+	if res, err := p.ParseDetailed(expr); err != nil {
+		return emptyTime, err
+	} else {
+		return res.Time, nil
+	}
+}
+
+// ParseDetailed parses date math expression like Parse, besides time it reports
+// the anchor, the matched format, the math operations and the precision of anchor.
+func (p *DateMathParser) ParseDetailed(expr string) (Result, error) {
+	switch p.Dialect {
+	case DialectGrafana:
+		return p.parseGrafana(expr)
+	case DialectSplunk:
+		return p.parseSplunk(expr)
+	}
+	var res = Result{}
 	var dur = ""
 	if len(expr) >= 3 && expr[0:3] == "now" {
 		dur = expr[3:]
-		res = time.Now()
+		res.Anchor = p.now()
+		res.IsNow = true
+		res.Precision = PrecisionNanosecond
 	} else {
 		var sep = strings.Index(expr, "||")
 		var err error
 		if sep == -1 {
 			dur = ""
-			if res, err = p.parseTime(expr); err != nil {
-				return emptyTime, err
-			}
+			sep = len(expr)
 		} else {
 			dur = expr[sep+2:]
-			if res, err = p.parseTime(expr[:sep]); err != nil {
-				return emptyTime, err
-			}
+		}
+		if res.Anchor, res.Format, res.Precision, err = p.parseAnchor(expr[:sep]); err != nil {
+			return Result{Time: emptyTime, Anchor: emptyTime}, err
+		}
+		if len(p.Formats) == 0 {
+			res.Ambiguous = p.ambiguous(expr[:sep])
 		}
 	}
-	res = res.UTC()
+	res.Anchor = p.roundPrecision(res.Anchor, res.Precision).UTC()
+	res.Time = res.Anchor
 	if dur == "" {
 		return res, nil
-	} else {
-		return p.evalDur(dur, res)
 	}
+	var err error
+	if res.Ops, err = parseDur(dur); err != nil {
+		return Result{Time: emptyTime, Anchor: emptyTime}, err
+	}
+	if res.Time, err = p.applyOps(res.Ops, res.Anchor); err != nil {
+		return Result{Time: emptyTime, Anchor: emptyTime}, err
+	}
+	return res, nil
+}
 
+// ParseRange parses lower and upper bound of time range like `from` and `to` of Grafana, upper bound
+// is rounded up regardless of RoundUp of parser, so `now-1d/d` to `now-1d/d` covers the whole yesterday.
+func (p *DateMathParser) ParseRange(from, to string) (time.Time, time.Time, error) {
+	var lower, upper = *p, *p
+	lower.RoundUp, upper.RoundUp = false, true
+	var start, err = lower.Parse(from)
+	if err != nil {
+		return emptyTime, emptyTime, err
+	}
+	end, err := upper.Parse(to)
+	if err != nil {
+		return emptyTime, emptyTime, err
+	}
+	return start, end, nil
 }
 
 func (p *DateMathParser) parseTime(expr string) (time.Time, error) {
+	var tim, _, _, err = p.parseAnchor(expr)
+	return tim, err
+}
+
+// parseAnchor parses anchor date, and returns time, matched format and precision.
+func (p *DateMathParser) parseAnchor(expr string) (time.Time, string, Precision, error) {
 	if len(p.Formats) != 0 {
-		for _, format := range p.Formats {
+		for i, format := range p.Formats {
 			if format == "epoch_second" {
 				if sec, err := strconv.ParseInt(expr, 10, 64); err != nil {
 					continue
-				} else if len(expr) <= 10 { // 秒的精度是10位
-					return time.Unix(sec, 0), nil
+				} else if tim, err := epochTime(sec, 0); err == nil {
+					return tim, p.formatName(i), PrecisionSecond, nil
 				}
 			} else if format == "epoch_millis" {
 				if millis, err := strconv.ParseInt(expr, 10, 64); err != nil {
 					continue
-				} else if len(expr) <= 13 { // 毫秒的精度是13位
-					return time.Unix(millis/1000, millis%1000), nil
+				} else if tim, err := epochTime(millis/1000, millis%1000*int64(time.Millisecond)); err == nil {
+					return tim, p.formatName(i), PrecisionMillisecond, nil
+				}
+			} else if format == EPOCH_AUTO {
+				if tim, precision, err := parseEpochAuto(expr); err == nil {
+					return tim, p.formatName(i), precision, nil
 				}
 			} else {
-				if tim, err := p.parseFormat(expr, format); err == nil {
-					return tim, nil
+				if tim, precision, err := p.parseFormat(expr, i); err == nil {
+					return tim, p.formatName(i), precision, nil
 				}
 			}
 		}
-		return emptyTime, fmt.Errorf("failed to parse time, expr: %s, format: %+v", expr, p.Formats)
+		return emptyTime, "", PrecisionUnknown, fmt.Errorf("failed to parse time, expr: %s, format: %+v", expr, p.Formats)
 	} else {
-		return p.parseAny(expr)
+		if tim, err := p.parseAny(expr); err != nil {
+			return emptyTime, "", PrecisionUnknown, err
+		} else {
+			return tim, "", p.anyPrecision(expr), nil
+		}
+	}
+}
+
+// formatName returns the name of i-th format, which is the pattern itself
+// when Formats is assigned directly.
+func (p *DateMathParser) formatName(i int) string {
+	if i < len(p.formatSources) {
+		return p.formatSources[i].name
+	}
+	return p.Formats[i]
+}
+
+func (p *DateMathParser) parseFormat(expr string, i int) (time.Time, Precision, error) {
+	if pat, err := p.pattern(i); err != nil {
+		return emptyTime, PrecisionUnknown, err
+	} else {
+		return pat.parse(expr, p.parseContext())
+	}
+}
+
+func (p *DateMathParser) parseContext() *parseContext {
+	var loc, ok = lookupLocale(p.Locale)
+	if !ok {
+		loc = englishLocale
+	}
+	var now = p.now()
+	var pivot = p.TwoDigitYearPivot
+	if p.twoDigitYearSliding {
+		pivot = now.In(p.location()).Year() - p.twoDigitYearWindow
+	}
+	var weekStart *time.Weekday
+	if p.weekStart != nil || p.hasLocaleWeekStart() {
+		var day = p.firstDayOfWeek()
+		weekStart = &day
+	}
+	return &parseContext{
+		loc:               p.location(),
+		lenientFraction:   p.LenientFraction,
+		locale:            loc,
+		now:               now,
+		yearInference:     p.YearInference,
+		twoDigitYearPivot: pivot,
+		weekStart:         weekStart,
 	}
 }
 
-func (p *DateMathParser) parseFormat(expr, format string) (time.Time, error) {
-	return jodaTime.ParseInLocation(format, expr, p.TimeZone.String())
+func (p *DateMathParser) now() time.Time {
+	if p.Now != nil {
+		return p.Now()
+	}
+	return time.Now()
 }
 
-func (p *DateMathParser) parseAny(expr string) (time.Time, error) {
-	return dateparse.ParseIn(expr, p.TimeZone)
+// Format formats tim in time zone and locale of parser, format is a built-in
+// format name, whose first pattern is used, or a pattern.
+func (p *DateMathParser) Format(tim time.Time, format string) (string, error) {
+	var dialect = p.PatternDialect
+	var options = patternOptions{}
+	if f, ok := p.lookupFormat(format); ok && len(f.patterns) != 0 {
+		format = f.patterns[0]
+		if f.builtIn {
+			dialect = PatternJoda
+			options = f.options
+		}
+	}
+	switch format {
+	case EPOCH_SECOND:
+		return strconv.FormatInt(tim.Unix(), 10), nil
+	case EPOCH_MILLIS, EPOCH_AUTO:
+		if millis, err := unixMillis(tim); err != nil {
+			return "", err
+		} else {
+			return strconv.FormatInt(millis, 10), nil
+		}
+	}
+	format, dialect = splitPatternDialect(format, dialect)
+	if pat, err := compilePattern(format, dialect); err != nil {
+		return "", err
+	} else {
+		pat.options = options
+		return pat.format(tim.In(p.location()), p.parseContext())
+	}
 }
 
 func (p *DateMathParser) evalDur(dur string, tim time.Time) (time.Time, error) {
-	var res = tim
+	if ops, err := parseDur(dur); err != nil {
+		return emptyTime, err
+	} else {
+		return p.applyOps(ops, tim)
+	}
+}
+
+// parseDur parses math expression like `+1d-2h/d` into operations.
+func parseDur(dur string) ([]MathOp, error) {
 	var allMatch = durRegexp.FindAllStringSubmatch(dur, -1)
 	if len(allMatch) == 0 {
-		return emptyTime, fmt.Errorf(`expect match expression: ([\+-]\d*|\/)(y|M|w|d|h|H|m|s)`)
+		return nil, fmt.Errorf(`expect match expression: ([\+-]\d*|\/\d*)(bd|fy|fQ|y|M|w|d|h|H|m|s)`)
 	}
+	var ops = make([]MathOp, 0, len(allMatch))
 	for _, s := range allMatch {
 		if s[1] == "/" {
-			res = res.Round(units[s[2]])
+			ops = append(ops, MathOp{Op: '/', Unit: s[2]})
+		} else if s[1][0] == '/' {
+			var n, err = strconv.Atoi(s[1][1:])
+			if err != nil {
+				return nil, &RangeError{Op: s[0], Reason: "amount overflows int"}
+			}
+			if n == 0 {
+				return nil, fmt.Errorf("rounding %s: count should be positive", s[0])
+			}
+			if _, ok := multipleUnits[s[2]]; !ok {
+				return nil, fmt.Errorf("rounding %s: unit %s doesn't support count", s[0], s[2])
+			}
+			ops = append(ops, MathOp{Op: '/', Amount: n, Unit: s[2]})
 		} else {
-			var d int
-			if s[1] == "-" {
-				d = -1
-			} else if s[1] == "+" {
-				d = 1
-			} else {
-				d, _ = strconv.Atoi(s[1])
+			var d = 1
+			if len(s[1]) > 1 {
+				var err error
+				if d, err = strconv.Atoi(s[1][1:]); err != nil {
+					return nil, &RangeError{Op: s[0], Reason: "amount overflows int"}
+				}
 			}
-			res = res.Add(time.Duration(d) * units[s[2]])
+			ops = append(ops, MathOp{Op: s[1][0], Amount: d, Unit: s[2]})
+		}
+	}
+	return ops, nil
+}
+
+// applyOps applies ops to tim in order, MaxOffset limits how far the arithmetic operations
+// move time in total, rounding doesn't count since it stays in the unit containing time.
+func (p *DateMathParser) applyOps(ops []MathOp, tim time.Time) (time.Time, error) {
+	var res = tim
+	// offset of arithmetic operations in seconds, since it may exceed time.Duration
+	var offset int64
+	for _, op := range ops {
+		var err error
+		var before = res
+		switch {
+		case op.Op == '/' && op.Unit == "bd":
+			res, err = p.roundBusinessDay(res)
+		case op.Op == '/' && op.Amount > 0:
+			res, err = p.roundMultiple(res, op)
+		case op.Op == '/':
+			res = p.roundUnit(res, op.Unit)
+		case op.Unit == "bd":
+			res, err = p.addBusinessDays(res, op)
+		case p.Dialect != DialectElasticSearch:
+			res, err = p.addCalendar(res, op)
+		default:
+			res, err = addUnits(res, op)
+		}
+		if err != nil {
+			return emptyTime, err
+		}
+		if op.Op == '/' || p.MaxOffset <= 0 {
+			continue
+		}
+		offset += res.Unix() - before.Unix()
+		if offset > int64(p.MaxOffset/time.Second) || -offset > int64(p.MaxOffset/time.Second) {
+			return emptyTime, &RangeError{Op: op.String(), Reason: fmt.Sprintf("offset from anchor exceeds %s", p.MaxOffset)}
 		}
 	}
 	return res, nil
 }
+
+// addUnits adds offset of op to tim in seconds, which works beyond time.Duration of
+// about 292 years like `now-500y`, and keeps year in [-999999999, 999999999].
+func addUnits(tim time.Time, op MathOp) (time.Time, error) {
+	var seconds = int64(units[op.Unit] / time.Second)
+	if int64(op.Amount) > math.MaxInt64/seconds {
+		return emptyTime, &RangeError{Op: op.String(), Reason: "offset overflows int64 seconds"}
+	}
+	var offset = int64(op.Amount) * seconds
+	if op.Op == '-' {
+		offset = -offset
+	}
+	var sec = tim.Unix()
+	if (offset > 0 && sec > maxEpochSecond-offset) || (offset < 0 && sec < minEpochSecond-offset) {
+		return emptyTime, &RangeError{Op: op.String(), Reason: "result exceeds year range [-999999999, 999999999]"}
+	}
+	return time.Unix(sec+offset, int64(tim.Nanosecond())).In(tim.Location()), nil
+}
+
+// calendarMonths are months of units added by calendar, instead of fixed seconds.
+var calendarMonths = map[string]int{"y": 12, "Q": 3, "M": 1, "fy": 12, "fQ": 3}
+
+// addCalendar adds units by calendar in time zone of parser, keeping time of day. Years, quarters
+// and months keep day of month unless target month is shorter, weeks and days keep time of day
+// across daylight saving changes, other units are added in seconds.
+func (p *DateMathParser) addCalendar(tim time.Time, op MathOp) (time.Time, error) {
+	var local = tim.In(p.location())
+	var year, month, day = local.Date()
+	var amount = op.Amount
+	if months, ok := calendarMonths[op.Unit]; ok {
+		if amount > (maxYear-minYear+1)*12/months {
+			return emptyTime, &RangeError{Op: op.String(), Reason: "result exceeds year range [-999999999, 999999999]"}
+		}
+		if op.Op == '-' {
+			amount = -amount
+		}
+		month += time.Month(amount * months)
+		// clamp day to the last day of target month, so `2021-05-31||+1fQ` is 2021-08-31 and `2021-11-30||+1fQ` is 2022-02-28
+		if last := time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day(); day > last {
+			day = last
+		}
+	} else if op.Unit == "w" || op.Unit == "d" {
+		var days = 1
+		if op.Unit == "w" {
+			days = 7
+		}
+		if amount > (maxYear-minYear+1)*366/days {
+			return emptyTime, &RangeError{Op: op.String(), Reason: "result exceeds year range [-999999999, 999999999]"}
+		}
+		if op.Op == '-' {
+			amount = -amount
+		}
+		day += amount * days
+	} else {
+		return addUnits(tim, op)
+	}
+	var res = time.Date(year, month, day, local.Hour(), local.Minute(), local.Second(), local.Nanosecond(), local.Location())
+	if res.Year() < minYear || res.Year() > maxYear {
+		return emptyTime, &RangeError{Op: op.String(), Reason: "result exceeds year range [-999999999, 999999999]"}
+	}
+	return res.In(tim.Location()), nil
+}
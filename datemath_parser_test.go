@@ -172,9 +172,9 @@ func TestDateMathParser_format(t *testing.T) {
 				"yyyy-DDDTHH"},
 			parser: &DateMathParser{
 				Formats: []string{"epoch_millis", "epoch_second",
-					"yyyy-MM-ddTHH:mm:ss.SSSZ", "yyyy-MM-dd",
-					"yyyy-MM-ddTHH:mm:ss.SSSSSSZ", "yyyy-MM-dd",
-					"yyyyDDDTHHmmssZ", "yyyy-DDDTHH"},
+					"yyyy[-MM[-dd]]['T'[HH[:mm[:ss][.SSS][,SSS]]][ZZ]]",
+					"yyyy[-MM[-dd]]['T'[HH[:mm[:ss[.SSSSSSSSS][,SSSSSSSSS]]]][ZZ]]",
+					"yyyyDDD'T'HHmmssZ", "yyyy-DDDTHH"},
 			},
 		},
 	} {
@@ -256,14 +256,14 @@ func TestDateMathParser_Parse(t *testing.T) {
 			name:    "TestDateMathParser_Parse01",
 			p:       &DateMathParser{},
 			args:    args{expr: "1640183392||+h/d"},
-			want:    time.Unix(1640183392+3600, 0).UTC().Round(time.Hour * 24),
+			want:    time.Unix(1640183392+3600, 0).UTC().Truncate(time.Hour * 24),
 			wantErr: false,
 		},
 		{
 			name:    "TestDateMathParser_Parse01_01",
 			p:       &DateMathParser{},
 			args:    args{expr: "1640183392||+2d/d"},
-			want:    time.Unix(1640183392+(2*24*3600), 0).UTC().Round(time.Hour * 24),
+			want:    time.Unix(1640183392+(2*24*3600), 0).UTC().Truncate(time.Hour * 24),
 			wantErr: false,
 		},
 
@@ -271,28 +271,28 @@ func TestDateMathParser_Parse(t *testing.T) {
 			name:    "TestDateMathParser_Parse02",
 			p:       &DateMathParser{},
 			args:    args{expr: "1640183392001||+h/d"},
-			want:    time.Unix(1640183392+3600, 1000000).UTC().Round(time.Hour * 24),
+			want:    time.Unix(1640183392+3600, 1000000).UTC().Truncate(time.Hour * 24),
 			wantErr: false,
 		},
 		{
 			name:    "TestDateMathParser_Parse03",
 			p:       &DateMathParser{TimeZone: time.Local},
 			args:    args{expr: "2021-12-22||+h+M/d"},
-			want:    time.Unix(1640102400+3600+3600*24*30, 0).UTC().Round(time.Hour * 24),
+			want:    time.Date(2022, 1, 21, 0, 0, 0, 0, time.Local).UTC(),
 			wantErr: false,
 		},
 		{
 			name:    "TestDateMathParser_Parse04",
 			p:       &DateMathParser{TimeZone: time.Local},
 			args:    args{expr: "2021-12-22T10:09:00||+h+M/d"},
-			want:    time.Unix(1640138940+3600+3600*24*30, 0).UTC().Round(time.Hour * 24),
+			want:    time.Date(2022, 1, 21, 0, 0, 0, 0, time.Local).UTC(),
 			wantErr: false,
 		},
 		{
 			name:    "TestDateMathParser_Parse05",
 			p:       &DateMathParser{TimeZone: time.UTC},
 			args:    args{expr: "now/s"},
-			want:    time.Now().UTC().Round(time.Second),
+			want:    time.Now().UTC().Truncate(time.Second),
 			wantErr: false,
 		},
 		{
@@ -380,3 +380,313 @@ func TestDateMathParser_Parse(t *testing.T) {
 		})
 	}
 }
+
+func TestDateMathParser_ParseDetailed(t *testing.T) {
+	tests := []struct {
+		name    string
+		formats []string
+		expr    string
+		want    Result
+		wantErr bool
+	}{
+		{
+			name:    "TestDateMathParser_ParseDetailed01",
+			formats: []string{"epoch_second", "date_optional_time"},
+			expr:    "2021-12-22||+1d/h",
+			want: Result{
+				Time:      time.Date(2021, 12, 23, 0, 0, 0, 0, time.UTC),
+				Anchor:    time.Date(2021, 12, 22, 0, 0, 0, 0, time.UTC),
+				Format:    "date_optional_time",
+				Ops:       []MathOp{{Op: '+', Amount: 1, Unit: "d"}, {Op: '/', Unit: "h"}},
+				Precision: PrecisionDay,
+			},
+		},
+		{
+			name:    "TestDateMathParser_ParseDetailed02",
+			formats: []string{"epoch_second", "yyyy-MM-dd HH:mm"},
+			expr:    "1640183392",
+			want: Result{
+				Time:      time.Unix(1640183392, 0).UTC(),
+				Anchor:    time.Unix(1640183392, 0).UTC(),
+				Format:    "epoch_second",
+				Precision: PrecisionSecond,
+			},
+		},
+		{
+			name:    "TestDateMathParser_ParseDetailed03",
+			formats: []string{"epoch_second", "yyyy-MM-dd HH:mm"},
+			expr:    "2021-12-22 10:09||-2h",
+			want: Result{
+				Time:      time.Date(2021, 12, 22, 8, 9, 0, 0, time.UTC),
+				Anchor:    time.Date(2021, 12, 22, 10, 9, 0, 0, time.UTC),
+				Format:    "yyyy-MM-dd HH:mm",
+				Ops:       []MathOp{{Op: '-', Amount: 2, Unit: "h"}},
+				Precision: PrecisionMinute,
+			},
+		},
+		{
+			name: "TestDateMathParser_ParseDetailed04",
+			expr: "2021-05",
+			want: Result{
+				Time:      time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+				Anchor:    time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+				Precision: PrecisionMonth,
+			},
+		},
+		{
+			name:    "TestDateMathParser_ParseDetailed05",
+			formats: []string{"date"},
+			expr:    "2021-12-22||+x",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p, _ = NewDateMathParser(WithFormat(tt.formats))
+			got, err := p.ParseDetailed(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DateMathParser.ParseDetailed() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("DateMathParser.ParseDetailed() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("TestDateMathParser_ParseDetailedNow", func(t *testing.T) {
+		var p, _ = NewDateMathParser()
+		got, err := p.ParseDetailed("now-1d")
+		if err != nil {
+			t.Fatalf("DateMathParser.ParseDetailed() error = %v", err)
+		}
+		if !got.IsNow || got.Format != "" || got.Precision != PrecisionNanosecond ||
+			!got.Time.Equal(got.Anchor.Add(-units["d"])) {
+			t.Errorf("DateMathParser.ParseDetailed() = %+v", got)
+		}
+	})
+}
+
+func TestPrecision(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		value   string
+		layout  bool
+		want    Precision
+	}{
+		{name: "TestPrecision01", pattern: "yyyy", value: "2021", want: PrecisionYear},
+		{name: "TestPrecision02", pattern: "yyyy-MM", value: "2021-05", want: PrecisionMonth},
+		{name: "TestPrecision03", pattern: "yyyy-MM-dd'T'HH", value: "2021-05-10T10", want: PrecisionHour},
+		{name: "TestPrecision04", pattern: "yyyy-MM-dd'T'HH:mm:ss.SSSZ", value: "2021-05-10T10:00:00.123Z", want: PrecisionMillisecond},
+		{name: "TestPrecision05", pattern: "yyyy-MM-dd'T'HH:mm:ss.SSSSSSZ", value: "2021-05-10T10:00:00.123456Z", want: PrecisionMicrosecond},
+		{name: "TestPrecision06", pattern: "xxxx-'W'ww", value: "2021-W05", want: PrecisionWeek},
+		{name: "TestPrecision07", pattern: "2006-01-02T15:04:05Z07:00", layout: true, want: PrecisionSecond},
+		{name: "TestPrecision08", pattern: "2006-01", layout: true, want: PrecisionMonth},
+		{name: "TestPrecision09", pattern: "Jan _2 15:04:05.000000", layout: true, want: PrecisionMicrosecond},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got Precision
+			if tt.layout {
+				got = layoutPrecision(tt.pattern)
+			} else {
+				var pat, _ = compilePattern(tt.pattern, PatternJoda)
+				var _, precision, err = pat.parse(tt.value, &parseContext{loc: time.UTC, locale: englishLocale})
+				if err != nil {
+					t.Fatalf("failed to parse %s, err: %+v", tt.value, err)
+				}
+				got = precision
+			}
+			if got != tt.want {
+				t.Errorf("precision of %s = %v, want %v", tt.pattern, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDateMathParser_RoundUp(t *testing.T) {
+	tests := []struct {
+		name     string
+		formats  []string
+		timeZone string
+		roundUp  bool
+		expr     string
+		want     time.Time
+	}{
+		{
+			name:    "TestDateMathParser_RoundUp01",
+			formats: []string{"year_month"},
+			roundUp: true,
+			expr:    "2021-05",
+			want:    time.Date(2021, 5, 31, 23, 59, 59, 999000000, time.UTC),
+		},
+		{
+			name:    "TestDateMathParser_RoundUp02",
+			formats: []string{"year"},
+			roundUp: true,
+			expr:    "2021",
+			want:    time.Date(2021, 12, 31, 23, 59, 59, 999000000, time.UTC),
+		},
+		{
+			name:    "TestDateMathParser_RoundUp03",
+			formats: []string{"year_month"},
+			expr:    "2021-05",
+			want:    time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "TestDateMathParser_RoundUp04",
+			formats: []string{"date_hour_minute_second"},
+			roundUp: true,
+			expr:    "2021-05-10T10:00:00||/M",
+			want:    time.Date(2021, 5, 31, 23, 59, 59, 999000000, time.UTC),
+		},
+		{
+			name:    "TestDateMathParser_RoundUp05",
+			formats: []string{"date_hour_minute_second"},
+			expr:    "2021-05-13T10:00:00||/w",
+			want:    time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "TestDateMathParser_RoundUp06",
+			formats:  []string{"date"},
+			timeZone: "+08:00",
+			roundUp:  true,
+			expr:     "2021-05-10",
+			want:     time.Date(2021, 5, 10, 15, 59, 59, 999000000, time.UTC),
+		},
+		{
+			name:     "TestDateMathParser_RoundUp07",
+			formats:  []string{"date_hour_minute_second"},
+			timeZone: "Asia/Shanghai",
+			expr:     "2021-05-10T06:00:00||/d",
+			want:     time.Date(2021, 5, 9, 16, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "TestDateMathParser_RoundUp08",
+			formats: []string{"java:uuuu-MM"},
+			roundUp: true,
+			expr:    "2021-02",
+			want:    time.Date(2021, 2, 28, 23, 59, 59, 999000000, time.UTC),
+		},
+		{
+			name:    "TestDateMathParser_RoundUp09",
+			formats: []string{"date_optional_time"},
+			roundUp: true,
+			expr:    "2021-05-10T10",
+			want:    time.Date(2021, 5, 10, 10, 59, 59, 999000000, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts = []DateMathParserOption{WithFormat(tt.formats), WithRoundUp(tt.roundUp)}
+			if tt.timeZone != "" {
+				opts = append(opts, WithTimeZone(tt.timeZone))
+			}
+			var p, _ = NewDateMathParser(opts...)
+			got, err := p.Parse(tt.expr)
+			if err != nil {
+				t.Errorf("DateMathParser.Parse() error = %v", err)
+			} else if !got.Equal(tt.want) {
+				t.Errorf("DateMathParser.Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDateMathParser_rangeError(t *testing.T) {
+	var anchor = time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name      string
+		opts      []DateMathParserOption
+		expr      string
+		want      time.Time
+		wantRange bool
+	}{
+		{name: "TestDateMathParser_rangeError01", expr: "now+999999y", want: anchor.AddDate(0, 0, 999999*365)},
+		{name: "TestDateMathParser_rangeError02", expr: "now+9999999999y", wantRange: true},
+		{name: "TestDateMathParser_rangeError03", expr: "now+99999999999999999999d", wantRange: true},
+		{name: "TestDateMathParser_rangeError04", expr: "now+9223372036854775807s", wantRange: true},
+		{name: "TestDateMathParser_rangeError05", expr: "now-1999999999y", wantRange: true},
+		{name: "TestDateMathParser_rangeError06", opts: []DateMathParserOption{WithMaxOffset(100 * 365 * 24 * time.Hour)}, expr: "now-100y", want: anchor.AddDate(0, 0, -100*365)},
+		{name: "TestDateMathParser_rangeError07", opts: []DateMathParserOption{WithMaxOffset(100 * 365 * 24 * time.Hour)}, expr: "now-101y", wantRange: true},
+		{name: "TestDateMathParser_rangeError08", opts: []DateMathParserOption{WithMaxOffset(100 * 365 * 24 * time.Hour)}, expr: "now-60y-60y", wantRange: true},
+		{name: "TestDateMathParser_rangeError09", opts: []DateMathParserOption{WithMaxOffset(24 * time.Hour)}, expr: "now-2d+1d-1s", wantRange: true},
+		{name: "TestDateMathParser_rangeError10", opts: []DateMathParserOption{WithMaxOffset(24 * time.Hour)}, expr: "now+1d-1d/y", want: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_rangeError11", opts: []DateMathParserOption{WithMaxOffset(time.Hour)}, expr: "2021-05-12T10:00:00||/d", want: time.Date(2021, 5, 12, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_rangeError12", opts: []DateMathParserOption{WithMaxOffset(time.Hour)}, expr: "2021-05-12T10:00:00||/y", want: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_rangeError13", opts: []DateMathParserOption{WithMaxOffset(time.Hour)}, expr: "2021-05-12T10:00:00||/1d", want: time.Date(2021, 5, 12, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_rangeError14", opts: []DateMathParserOption{WithMaxOffset(time.Hour)}, expr: "2021-05-12T10:00:00||/1y", want: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_rangeError15", opts: []DateMathParserOption{WithMaxOffset(time.Hour)}, expr: "2021-05-12T10:00:00||/bd", want: time.Date(2021, 5, 12, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_rangeError16", opts: []DateMathParserOption{WithMaxOffset(time.Hour)}, expr: "2021-05-12T10:00:00||/y+1h", want: time.Date(2021, 1, 1, 1, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_rangeError17", opts: []DateMathParserOption{WithMaxOffset(time.Hour)}, expr: "2021-05-12T10:00:00||/y+2h", wantRange: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts = append([]DateMathParserOption{WithNow(func() time.Time { return anchor })}, tt.opts...)
+			var p, err = NewDateMathParser(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got, perr = p.Parse(tt.expr)
+			if _, ok := perr.(*RangeError); ok != tt.wantRange {
+				t.Errorf("Parse() error = %v, want range error %v", perr, tt.wantRange)
+				return
+			}
+			if !tt.wantRange && (perr != nil || !got.Equal(tt.want)) {
+				t.Errorf("Parse() = %v, %v, want %v", got, perr, tt.want)
+			}
+		})
+	}
+	if _, err := NewDateMathParser(WithMaxOffset(-time.Second)); err == nil {
+		t.Errorf("expect error for negative max offset")
+	}
+}
+
+func TestDateMathParser_roundMultiple(t *testing.T) {
+	var now = time.Date(2021, 5, 10, 10, 37, 42, 0, time.UTC)
+	tests := []struct {
+		name    string
+		opts    []DateMathParserOption
+		expr    string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "TestDateMathParser_roundMultiple01", expr: "now/15m", want: time.Date(2021, 5, 10, 10, 30, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_roundMultiple02", expr: "now-1h/15m", want: time.Date(2021, 5, 10, 9, 30, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_roundMultiple03", expr: "now/6h", want: time.Date(2021, 5, 10, 6, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_roundMultiple04", opts: []DateMathParserOption{WithRoundUp(true)}, expr: "now/6h", want: time.Date(2021, 5, 10, 11, 59, 59, 999000000, time.UTC)},
+		{name: "TestDateMathParser_roundMultiple05", opts: []DateMathParserOption{WithTimeZone("+05:30")}, expr: "now/6h", want: time.Date(2021, 5, 10, 6, 30, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_roundMultiple06", opts: []DateMathParserOption{WithRoundOffset(time.Hour)}, expr: "now/6h", want: time.Date(2021, 5, 10, 7, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_roundMultiple07", opts: []DateMathParserOption{WithRoundOffset(-30 * time.Minute)}, expr: "now/6h", want: time.Date(2021, 5, 10, 5, 30, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_roundMultiple08", expr: "now/1m", want: time.Date(2021, 5, 10, 10, 37, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_roundMultiple09", expr: "now/3M", want: time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_roundMultiple10", opts: []DateMathParserOption{WithRoundUp(true)}, expr: "now/3M", want: time.Date(2021, 6, 30, 23, 59, 59, 999000000, time.UTC)},
+		{name: "TestDateMathParser_roundMultiple11", expr: "now/10y", want: time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_roundMultiple12", expr: "now/7d", want: time.Date(2021, 5, 6, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_roundMultiple13", expr: "1969-12-31||/7d", want: time.Date(1969, 12, 25, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_roundMultiple14", expr: "now/0m", wantErr: true},
+		{name: "TestDateMathParser_roundMultiple15", expr: "now/2w", wantErr: true},
+		{name: "TestDateMathParser_roundMultiple16", expr: "now/2bd", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts = append([]DateMathParserOption{WithNow(func() time.Time { return now }), WithFormat([]string{"date"})}, tt.opts...)
+			var p, err = NewDateMathParser(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got, perr = p.Parse(tt.expr)
+			if (perr != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", perr, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := (MathOp{Op: '/', Amount: 15, Unit: "m"}).String(); got != "/15m" {
+		t.Errorf("String() = %s, want /15m", got)
+	}
+}
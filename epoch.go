@@ -0,0 +1,103 @@
+package datemath_parser
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// epochUnits classify epoch_auto value by magnitude of its integer part, a value less than
+// limit is counted in the unit. 1e11 seconds and 1e11 millis are 5138-11-16 and 1973-03-03,
+// so millis before 1973-03-03 are read as seconds, micros and nanos have the same limitation.
+var epochUnits = []struct {
+	limit     int64
+	unit      time.Duration
+	digits    int // count of digits of fraction of second in the unit
+	precision Precision
+}{
+	{limit: 1e11, unit: time.Second, digits: 0, precision: PrecisionSecond},
+	{limit: 1e14, unit: time.Millisecond, digits: 3, precision: PrecisionMillisecond},
+	{limit: 1e17, unit: time.Microsecond, digits: 6, precision: PrecisionMicrosecond},
+	{limit: 1<<63 - 1, unit: time.Nanosecond, digits: 9, precision: PrecisionNanosecond},
+}
+
+// parseEpochAuto parses epoch in seconds, millis, micros or nanos decided by magnitude,
+// the value may be negative and may have decimal fraction of the unit like `1620640800.123`.
+func parseEpochAuto(expr string) (time.Time, Precision, error) {
+	var value, fraction = expr, ""
+	if i := strings.IndexByte(expr, '.'); i != -1 {
+		value, fraction = expr[:i], expr[i+1:]
+		if fraction == "" || !isDigits(fraction) {
+			return emptyTime, PrecisionUnknown, fmt.Errorf("epoch: %s is invalid", expr)
+		}
+	}
+	var negative = strings.HasPrefix(value, "-")
+	var digits = strings.TrimPrefix(strings.TrimPrefix(value, "-"), "+")
+	if digits == "" || !isDigits(digits) {
+		return emptyTime, PrecisionUnknown, fmt.Errorf("epoch: %s is invalid", expr)
+	}
+	var n, err = strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return emptyTime, PrecisionUnknown, fmt.Errorf("epoch: %s is out of range", expr)
+	}
+	for _, u := range epochUnits {
+		if n >= u.limit && u.unit != time.Nanosecond {
+			continue
+		}
+		var perSecond = int64(time.Second / u.unit)
+		var sec, nsec = n / perSecond, n % perSecond * int64(u.unit)
+		// fraction of the unit is truncated to nanosecond
+		var fractionDigits = len(fraction)
+		if fractionDigits > 9-u.digits {
+			fraction = fraction[:9-u.digits]
+		}
+		if fraction != "" {
+			var f, _ = strconv.ParseInt(fraction, 10, 64)
+			for i := len(fraction); i < 9-u.digits; i++ {
+				f *= 10
+			}
+			nsec += f
+		}
+		if negative {
+			sec, nsec = -sec, -nsec
+		}
+		var precision = u.precision
+		if fractionDigits != 0 {
+			precision = fractionPrecision(u.digits + fractionDigits)
+		}
+		var tim, err = epochTime(sec, nsec)
+		return tim, precision, err
+	}
+	return emptyTime, PrecisionUnknown, fmt.Errorf("epoch: %s is out of range", expr)
+}
+
+// minYear and maxYear are limits of year like java.time.
+const (
+	minYear = -999999999
+	maxYear = 999999999
+)
+
+// minEpochSecond and maxEpochSecond are limits of epoch, which keep year in [minYear, maxYear].
+var (
+	minEpochSecond = time.Date(minYear, time.January, 1, 0, 0, 0, 0, time.UTC).Unix()
+	maxEpochSecond = time.Date(maxYear, time.December, 31, 23, 59, 59, 0, time.UTC).Unix()
+)
+
+// epochTime is time.Unix with range check.
+func epochTime(sec, nsec int64) (time.Time, error) {
+	if sec < minEpochSecond || sec > maxEpochSecond {
+		return emptyTime, fmt.Errorf("epoch: %d is out of range [%d, %d] seconds", sec, minEpochSecond, maxEpochSecond)
+	}
+	return time.Unix(sec, nsec), nil
+}
+
+// unixMillis returns milliseconds since epoch, which can't use UnixNano limited in 1678~2262.
+func unixMillis(tim time.Time) (int64, error) {
+	var sec = tim.Unix()
+	if sec > math.MaxInt64/1000-1 || sec < math.MinInt64/1000+1 {
+		return 0, fmt.Errorf("time: %s is out of range of epoch_millis", tim)
+	}
+	return sec*1000 + int64(tim.Nanosecond())/int64(time.Millisecond), nil
+}
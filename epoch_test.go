@@ -0,0 +1,69 @@
+package datemath_parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseEpochAuto(t *testing.T) {
+	tests := []struct {
+		name          string
+		expr          string
+		want          time.Time
+		wantPrecision Precision
+		wantErr       bool
+	}{
+		{name: "TestParseEpochAuto01", expr: "1620640800", want: time.Unix(1620640800, 0), wantPrecision: PrecisionSecond},
+		{name: "TestParseEpochAuto02", expr: "1620640800123", want: time.Unix(1620640800, 123000000), wantPrecision: PrecisionMillisecond},
+		{name: "TestParseEpochAuto03", expr: "1620640800123456", want: time.Unix(1620640800, 123456000), wantPrecision: PrecisionMicrosecond},
+		{name: "TestParseEpochAuto04", expr: "1620640800123456789", want: time.Unix(1620640800, 123456789), wantPrecision: PrecisionNanosecond},
+		{name: "TestParseEpochAuto05", expr: "99999999999", want: time.Unix(99999999999, 0), wantPrecision: PrecisionSecond},
+		{name: "TestParseEpochAuto06", expr: "100000000000", want: time.Unix(100000000, 0), wantPrecision: PrecisionMillisecond},
+		{name: "TestParseEpochAuto07", expr: "99999999999999", want: time.Unix(99999999999, 999000000), wantPrecision: PrecisionMillisecond},
+		{name: "TestParseEpochAuto08", expr: "100000000000000", want: time.Unix(100000000, 0), wantPrecision: PrecisionMicrosecond},
+		{name: "TestParseEpochAuto09", expr: "99999999999999999", want: time.Unix(99999999999, 999999000), wantPrecision: PrecisionMicrosecond},
+		{name: "TestParseEpochAuto10", expr: "100000000000000000", want: time.Unix(100000000, 0), wantPrecision: PrecisionNanosecond},
+		{name: "TestParseEpochAuto11", expr: "0", want: time.Unix(0, 0), wantPrecision: PrecisionSecond},
+		{name: "TestParseEpochAuto12", expr: "-86400", want: time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC), wantPrecision: PrecisionSecond},
+		{name: "TestParseEpochAuto13", expr: "-1620640800123", want: time.Unix(-1620640800, -123000000), wantPrecision: PrecisionMillisecond},
+		{name: "TestParseEpochAuto14", expr: "1620640800.123", want: time.Unix(1620640800, 123000000), wantPrecision: PrecisionMillisecond},
+		{name: "TestParseEpochAuto15", expr: "1620640800.1234567891", want: time.Unix(1620640800, 123456789), wantPrecision: PrecisionNanosecond},
+		{name: "TestParseEpochAuto16", expr: "1620640800123.5", want: time.Unix(1620640800, 123500000), wantPrecision: PrecisionMicrosecond},
+		{name: "TestParseEpochAuto17", expr: "-1.5", want: time.Unix(-2, 500000000), wantPrecision: PrecisionMillisecond},
+		{name: "TestParseEpochAuto18", expr: "+1620640800", want: time.Unix(1620640800, 0), wantPrecision: PrecisionSecond},
+		{name: "TestParseEpochAuto19", expr: "1620640800.", wantErr: true},
+		{name: "TestParseEpochAuto20", expr: "1620640800.1e3", wantErr: true},
+		{name: "TestParseEpochAuto21", expr: "-", wantErr: true},
+		{name: "TestParseEpochAuto22", expr: "99999999999999999999", wantErr: true},
+		{name: "TestParseEpochAuto23", expr: "2021-05-10", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got, precision, err = parseEpochAuto(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parseEpochAuto() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && (!got.Equal(tt.want) || precision != tt.wantPrecision) {
+				t.Errorf("parseEpochAuto() = %v %v, want %v %v", got.UTC(), precision, tt.want.UTC(), tt.wantPrecision)
+			}
+		})
+	}
+}
+
+func TestDateMathParser_epochAuto(t *testing.T) {
+	var p, _ = NewDateMathParser(WithFormat([]string{EPOCH_AUTO, DATE}))
+	if res, err := p.ParseDetailed("1620640800123||+1d"); err != nil || !res.Time.Equal(time.Unix(1620727200, 123000000)) || res.Format != EPOCH_AUTO {
+		t.Errorf("ParseDetailed() = %+v, %v", res, err)
+	}
+	if res, err := p.ParseDetailed("2021-05-10"); err != nil || res.Format != DATE {
+		t.Errorf("ParseDetailed() = %+v, %v", res, err)
+	}
+	if s, err := p.Format(time.Unix(1620640800, 123000000), EPOCH_AUTO); err != nil || s != "1620640800123" {
+		t.Errorf("Format() = %s, %v", s, err)
+	}
+	p, _ = NewDateMathParser(WithFormat([]string{EPOCH_MILLIS}))
+	if got, err := p.Parse("1620640800123"); err != nil || !got.Equal(time.Unix(1620640800, 123000000)) {
+		t.Errorf("Parse() = %v, %v", got, err)
+	}
+}
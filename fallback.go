@@ -0,0 +1,40 @@
+package datemath_parser
+
+import (
+	"fmt"
+	"regexp"
+	"time"
+
+	"github.com/araddon/dateparse"
+)
+
+// dottedDate matches numeric date separated by dots like `31.03.2021`, which dateparse
+// always reads as month first, so it's rewritten with slashes to follow DayFirst.
+var dottedDate = regexp.MustCompile(`^(\d{1,2})\.(\d{1,2})\.(\d{2}|\d{4})\b`)
+
+// parseAny guesses format of expr by dateparse, which is used when no format is given.
+func (p *DateMathParser) parseAny(expr string) (time.Time, error) {
+	if p.Strict {
+		return emptyTime, fmt.Errorf("failed to parse time, expr: %s, no format is given in strict mode", expr)
+	}
+	expr = dottedDate.ReplaceAllString(expr, "$1/$2/$3")
+	var tim, err = dateparse.ParseIn(expr, p.TimeZone, dateparse.PreferMonthFirst(!p.DayFirst))
+	if err != nil {
+		// the preferred order may be out of range like `31/03/2021` of month first
+		if swapped, swapErr := dateparse.ParseIn(expr, p.TimeZone, dateparse.PreferMonthFirst(p.DayFirst)); swapErr == nil {
+			return swapped, nil
+		}
+	}
+	return tim, err
+}
+
+// ambiguous reports whether expr is valid date in both orders of day and month, which are different.
+func (p *DateMathParser) ambiguous(expr string) bool {
+	expr = dottedDate.ReplaceAllString(expr, "$1/$2/$3")
+	if _, err := dateparse.ParseStrict(expr); err != dateparse.ErrAmbiguousMMDD {
+		return false
+	}
+	var monthFirst, monthErr = dateparse.ParseIn(expr, p.location(), dateparse.PreferMonthFirst(true))
+	var dayFirst, dayErr = dateparse.ParseIn(expr, p.location(), dateparse.PreferMonthFirst(false))
+	return monthErr == nil && dayErr == nil && !monthFirst.Equal(dayFirst)
+}
@@ -0,0 +1,126 @@
+package datemath_parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateMathParser_fallback(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []DateMathParserOption
+		expr          string
+		want          time.Time
+		wantAmbiguous bool
+		wantErr       bool
+	}{
+		{
+			name:          "TestDateMathParser_fallback01",
+			expr:          "01/02/2021",
+			want:          time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+			wantAmbiguous: true,
+		},
+		{
+			name:          "TestDateMathParser_fallback02",
+			opts:          []DateMathParserOption{WithDayFirst(true)},
+			expr:          "01/02/2021",
+			want:          time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC),
+			wantAmbiguous: true,
+		},
+		{
+			name: "TestDateMathParser_fallback03",
+			expr: "31/03/2021",
+			want: time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "TestDateMathParser_fallback04",
+			opts: []DateMathParserOption{WithDayFirst(true)},
+			expr: "03/31/2021",
+			want: time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:          "TestDateMathParser_fallback05",
+			opts:          []DateMathParserOption{WithDayFirst(true)},
+			expr:          "03.04.2021 10:20",
+			want:          time.Date(2021, 4, 3, 10, 20, 0, 0, time.UTC),
+			wantAmbiguous: true,
+		},
+		{
+			name: "TestDateMathParser_fallback06",
+			opts: []DateMathParserOption{WithDayFirst(true)},
+			expr: "31.03.2021",
+			want: time.Date(2021, 3, 31, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "TestDateMathParser_fallback07",
+			expr: "3.3.2014",
+			want: time.Date(2014, 3, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "TestDateMathParser_fallback08",
+			opts: []DateMathParserOption{WithDayFirst(true)},
+			expr: "2021-01-02",
+			want: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "TestDateMathParser_fallback09",
+			opts:    []DateMathParserOption{WithStrict(true)},
+			expr:    "2021-01-02",
+			wantErr: true,
+		},
+		{
+			name: "TestDateMathParser_fallback10",
+			opts: []DateMathParserOption{WithStrict(true), WithFormat([]string{DATE})},
+			expr: "2021-01-02",
+			want: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p, err = NewDateMathParser(tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var res, perr = p.ParseDetailed(tt.expr)
+			if (perr != nil) != tt.wantErr {
+				t.Errorf("ParseDetailed() error = %v, wantErr %v", perr, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && (!res.Time.Equal(tt.want) || res.Ambiguous != tt.wantAmbiguous) {
+				t.Errorf("ParseDetailed() = %v %v, want %v %v", res.Time, res.Ambiguous, tt.want, tt.wantAmbiguous)
+			}
+		})
+	}
+	var p, _ = NewDateMathParser(WithStrict(true))
+	if _, err := p.Parse("now-1d"); err != nil {
+		t.Errorf("now should be parsed in strict mode, %v", err)
+	}
+}
+
+func TestDateMathParser_fallbackPrecision(t *testing.T) {
+	tests := []struct {
+		name      string
+		opts      []DateMathParserOption
+		expr      string
+		precision Precision
+		want      time.Time
+	}{
+		{name: "TestDateMathParser_fallbackPrecision01", expr: "31/03/2021", precision: PrecisionDay, want: time.Date(2021, 3, 31, 23, 59, 59, 999000000, time.UTC)},
+		{name: "TestDateMathParser_fallbackPrecision02", expr: "31.03.2021", precision: PrecisionDay, want: time.Date(2021, 3, 31, 23, 59, 59, 999000000, time.UTC)},
+		{name: "TestDateMathParser_fallbackPrecision03", opts: []DateMathParserOption{WithDayFirst(true)}, expr: "31.03.2021 10:20", precision: PrecisionMinute, want: time.Date(2021, 3, 31, 10, 20, 59, 999000000, time.UTC)},
+		{name: "TestDateMathParser_fallbackPrecision04", opts: []DateMathParserOption{WithDayFirst(true)}, expr: "03/31/2021", precision: PrecisionDay, want: time.Date(2021, 3, 31, 23, 59, 59, 999000000, time.UTC)},
+		{name: "TestDateMathParser_fallbackPrecision05", opts: []DateMathParserOption{WithDayFirst(true)}, expr: "01/02/2021", precision: PrecisionDay, want: time.Date(2021, 2, 1, 23, 59, 59, 999000000, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p, err = NewDateMathParser(append([]DateMathParserOption{WithRoundUp(true)}, tt.opts...)...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var res, perr = p.ParseDetailed(tt.expr)
+			if perr != nil || res.Precision != tt.precision || !res.Time.Equal(tt.want) {
+				t.Errorf("ParseDetailed() = %v %v, %v, want %v %v", res.Time, res.Precision, perr, tt.want, tt.precision)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package datemath_parser
+
+import (
+	"time"
+)
+
+// fiscalStart returns the first month of fiscal year, january by default.
+func (p *DateMathParser) fiscalStart() time.Month {
+	if p.FiscalYearStartMonth == 0 {
+		return time.January
+	}
+	return p.FiscalYearStartMonth
+}
+
+// floorFiscal returns start of fiscal year or fiscal quarter containing tim, tim is in time zone of parser.
+func (p *DateMathParser) floorFiscal(tim time.Time, unit string) time.Time {
+	var year, month, _ = tim.Date()
+	// months passed since start of fiscal year
+	var passed = (int(month) - int(p.fiscalStart()) + 12) % 12
+	if unit == "fQ" {
+		passed %= 3
+	}
+	return time.Date(year, month-time.Month(passed), 1, 0, 0, 0, 0, tim.Location())
+}
@@ -0,0 +1,63 @@
+package datemath_parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateMathParser_fiscal(t *testing.T) {
+	// 2021-05-15 is in Q1 of fiscal year starting in april
+	var now = time.Date(2021, 5, 15, 10, 30, 0, 0, time.UTC)
+	var april = WithFiscalYearStartMonth(time.April)
+	tests := []struct {
+		name      string
+		opts      []DateMathParserOption
+		expr      string
+		want      time.Time
+		wantRange bool
+	}{
+		{name: "TestDateMathParser_fiscal01", expr: "now/fy", want: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_fiscal02", expr: "now/fQ", want: time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_fiscal03", opts: []DateMathParserOption{april}, expr: "now/fy", want: time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_fiscal04", opts: []DateMathParserOption{april}, expr: "now-2M/fy", want: time.Date(2020, 4, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_fiscal05", opts: []DateMathParserOption{april, WithRoundUp(true)}, expr: "now/fy", want: time.Date(2022, 3, 31, 23, 59, 59, 999000000, time.UTC)},
+		{name: "TestDateMathParser_fiscal06", opts: []DateMathParserOption{WithFiscalYearStartMonth(time.February)}, expr: "now/fQ", want: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_fiscal07", opts: []DateMathParserOption{WithFiscalYearStartMonth(time.February), WithRoundUp(true)}, expr: "now/fQ", want: time.Date(2021, 7, 31, 23, 59, 59, 999000000, time.UTC)},
+		{name: "TestDateMathParser_fiscal08", opts: []DateMathParserOption{april}, expr: "now-1fQ/fQ", want: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_fiscal09", expr: "now+1fy", want: time.Date(2022, 5, 15, 10, 30, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_fiscal10", expr: "now-3fQ", want: time.Date(2020, 8, 18, 10, 30, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_fiscal11", opts: []DateMathParserOption{WithDialect(DialectGrafana)}, expr: "2021-11-30||+1fQ", want: time.Date(2022, 2, 28, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_fiscal12", opts: []DateMathParserOption{WithTimeZone("+08:00")}, expr: "now/fy", want: time.Date(2020, 12, 31, 16, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_fiscal13", expr: "now+1100000000fy", wantRange: true},
+		{name: "TestDateMathParser_fiscal14", expr: "now-99999999999fQ", wantRange: true},
+		// fiscal units are added like `y` and `3M` of the same dialect
+		{name: "TestDateMathParser_fiscal15", expr: "2024-01-15||+1fy", want: time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_fiscal16", expr: "2024-01-15||+1y", want: time.Date(2025, 1, 14, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_fiscal17", expr: "2021-01-31||+1fQ", want: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_fiscal18", expr: "2021-01-31||+3M", want: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_fiscal19", opts: []DateMathParserOption{WithDialect(DialectGrafana)}, expr: "2024-01-15||+1fy", want: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_fiscal20", opts: []DateMathParserOption{WithDialect(DialectGrafana)}, expr: "2021-01-31||+1fQ", want: time.Date(2021, 4, 30, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts = append([]DateMathParserOption{WithNow(func() time.Time { return now }), WithFormat([]string{"date"})}, tt.opts...)
+			var p, err = NewDateMathParser(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got, perr = p.Parse(tt.expr)
+			if _, ok := perr.(*RangeError); ok != tt.wantRange {
+				t.Errorf("Parse() error = %v, want range error %v", perr, tt.wantRange)
+				return
+			}
+			if !tt.wantRange && (perr != nil || !got.Equal(tt.want)) {
+				t.Errorf("Parse() = %v, %v, want %v", got, perr, tt.want)
+			}
+		})
+	}
+	for _, month := range []time.Month{0, 13} {
+		if _, err := NewDateMathParser(WithFiscalYearStartMonth(month)); err == nil {
+			t.Errorf("expect error for fiscal year start month %d", month)
+		}
+	}
+}
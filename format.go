@@ -1,8 +1,13 @@
 package datemath_parser
 
+import (
+	"strings"
+)
+
 const (
 	EPOCH_MILLIS                            = "epoch_millis"
 	EPOCH_SECOND                            = "epoch_second"
+	EPOCH_AUTO                              = "epoch_auto"
 	DATE_OPTIONAL_TIME                      = "date_optional_time"
 	STRICT_DATE_OPTIONAL_TIME               = "strict_date_optional_time"
 	STRICT_DATE_OPTIONAL_TIME_NANOS         = "strict_date_optional_time_nanos"
@@ -80,9 +85,20 @@ const (
 	STRICT_YEAR_MONTH                       = "strict_year_month"
 	STRICT_YEAR                             = "strict_year"
 	STRICT_YEAR_MONTH_DAY                   = "strict_year_month_day"
+
+	RFC3339          = "rfc3339"
+	RFC3339_NANO     = "rfc3339_nano"
+	RFC2822          = "rfc2822"
+	RFC1123          = "rfc1123"
+	HTTP_DATE        = "http_date"
+	RFC3164          = "rfc3164"
+	COMMON_LOG       = "common_log"
+	ISO8601_BASIC    = "iso8601_basic"
+	ISO8601_EXTENDED = "iso8601_extended"
 )
 
-// built-in format
+// built-in format, patterns are joda syntax unless they start with dialect prefix like `java:` or
+// `strftime:`, each pattern has its own prefix, which WithFormat strips to select syntax of the pattern.
 var BuiltInFormat = map[string][]string{
 	// A formatter for the number of milliseconds since the epoch.
 	// Note, that this timestamp is subject to the limits of a Java Long.MIN_VALUE and Long.MAX_VALUE.
@@ -91,30 +107,35 @@ var BuiltInFormat = map[string][]string{
 	// Note, that this timestamp is subject to the limits of a Java Long.MIN_VALUE and Long.
 	// MAX_VALUE divided by 1000 (the number of milliseconds in a second).
 	EPOCH_SECOND: {EPOCH_SECOND},
+	// A formatter for the number of seconds, milliseconds, microseconds or nanoseconds since the epoch, the unit is decided by magnitude:
+	// less than 1e11 is seconds, less than 1e14 is milliseconds, less than 1e17 is microseconds, otherwise nanoseconds.
+	// Value may be negative and have decimal fraction of the unit like 1620640800.123, it's printed as milliseconds.
+	EPOCH_AUTO: {EPOCH_AUTO},
 
 	// A generic ISO datetime parser, where the date must include the year at a minimum, and the time (separated by T), is optional. Examples: yyyy-MM-dd'T'HH:mm:ss.SSSZ or yyyy-MM-dd.
-	DATE_OPTIONAL_TIME:        {"yyyy-MM-ddTHH:mm:ss.SSSZ", "yyyy-MM-dd"},
-	STRICT_DATE_OPTIONAL_TIME: {"yyyy-MM-ddTHH:mm:ss.SSSZ", "yyyy-MM-dd"},
+	// Month, day, each time field, fraction (separated by . or ,) and zone offset are optional sections like ElasticSearch.
+	DATE_OPTIONAL_TIME:        {"yyyy[-MM[-dd]]['T'[HH[:mm[:ss][.SSS][,SSS]]][ZZ]]"},
+	STRICT_DATE_OPTIONAL_TIME: {"yyyy[-MM[-dd]]['T'[HH[:mm[:ss[.SSS][,SSS]]]][ZZ]]"},
 	// A generic ISO datetime parser, where the date must include the year at a minimum, and the time (separated by T), is optional. The fraction of a second part has a nanosecond resolution. Examples: yyyy-MM-ddTHH:mm:ss.SSSSSSZ or yyyy-MM-dd.
-	STRICT_DATE_OPTIONAL_TIME_NANOS: {"yyyy-MM-ddTHH:mm:ss.SSSSSSZ", "yyyy-MM-dd"},
+	STRICT_DATE_OPTIONAL_TIME_NANOS: {"yyyy[-MM[-dd]]['T'[HH[:mm[:ss[.SSSSSSSSS][,SSSSSSSSS]]]][ZZ]]"},
 
 	// A basic formatter for a full date as four digit year, two digit month of year, and two digit day of month: yyyyMMdd.
 	BASIC_DATE: {"yyyyMMdd"},
 
 	// A basic formatter that combines a basic date and time, separated by a T: .
-	BASIC_DATE_TIME: {"yyyyMMddTHHmmss.SSSZ"},
+	BASIC_DATE_TIME: {"yyyyMMdd'T'HHmmss.SSSZ"},
 
 	// A basic formatter that combines a basic date and time without millis, separated by a T: yyyyMMddTHHmmssZ.
-	BASIC_DATE_TIME_NO_MILLIS: {"yyyyMMddTHHmmssZ"},
+	BASIC_DATE_TIME_NO_MILLIS: {"yyyyMMdd'T'HHmmssZ"},
 
 	// A formatter for a full ordinal date, using a four digit year and three digit dayOfYear: yyyyDDD.
 	BASIC_ORDINAL_DATE: {"yyyyDDD"},
 
 	// A formatter for a full ordinal date and time, using a four digit year and three digit dayOfYear: yyyyDDDTHHmmss.SSSZ.
-	BASIC_ORDINAL_DATE_TIME: {"yyyyDDDTHHmmss.SSSZ"},
+	BASIC_ORDINAL_DATE_TIME: {"yyyyDDD'T'HHmmss.SSSZ"},
 
 	// A formatter for a full ordinal date and time without millis, using a four digit year and three digit dayOfYear: yyyyDDDTHHmmssZ.
-	BASIC_ORDINAL_DATE_TIME_NO_MILLIS: {"yyyyDDDTHHmmssZ"},
+	BASIC_ORDINAL_DATE_TIME_NO_MILLIS: {"yyyyDDD'T'HHmmssZ"},
 
 	// A basic formatter for a two digit hour of day, two digit minute of hour, two digit second of minute, three digit millis, and time zone offset: HHmmss.SSSZ.
 	BASIC_TIME: {"HHmmss.SSSZ"},
@@ -123,54 +144,54 @@ var BuiltInFormat = map[string][]string{
 	BASIC_TIME_NO_MILLIS: {"HHmmssZ"},
 
 	// A basic formatter for a two digit hour of day, two digit minute of hour, two digit second of minute, three digit millis, and time zone off set prefixed by T: THHmmss.SSSZ.
-	BASIC_T_TIME: {"THHmmss.SSSZ"},
+	BASIC_T_TIME: {"'T'HHmmss.SSSZ"},
 
 	// A basic formatter for a two digit hour of day, two digit minute of hour, two digit second of minute, and time zone offset prefixed by T: THHmmssZ.
-	BASIC_T_TIME_NO_MILLIS: {"THHmmssZ"},
+	BASIC_T_TIME_NO_MILLIS: {"'T'HHmmssZ"},
 
 	// A basic formatter for a full date as four digit weekyear, two digit week of weekyear, and one digit day of week: xxxxWwwe.
-	BASIC_WEEK_DATE:        {"xxxxWwwe"},
-	STRICT_BASIC_WEEK_DATE: {"xxxxWwwe"},
+	BASIC_WEEK_DATE:        {"xxxx'W'wwe"},
+	STRICT_BASIC_WEEK_DATE: {"xxxx'W'wwe"},
 
 	// A basic formatter that combines a basic weekyear date and time, separated by a T: xxxxWwweTHHmmss.SSSZ.
-	BASIC_WEEK_DATE_TIME:        {"xxxxWwweTHHmmss.SSSZ"},
-	STRICT_BASIC_WEEK_DATE_TIME: {"xxxxWwweTHHmmss.SSSZ"},
+	BASIC_WEEK_DATE_TIME:        {"xxxx'W'wwe'T'HHmmss.SSSZ"},
+	STRICT_BASIC_WEEK_DATE_TIME: {"xxxx'W'wwe'T'HHmmss.SSSZ"},
 
 	// A basic formatter that combines a basic weekyear date and time without millis, separated by a T: xxxxWwweTHHmmssZ.
-	BASIC_WEEK_DATE_TIME_NO_MILLIS:        {"xxxxWwweTHHmmssZ"},
-	STRICT_BASIC_WEEK_DATE_TIME_NO_MILLIS: {"xxxxWwweTHHmmssZ"},
+	BASIC_WEEK_DATE_TIME_NO_MILLIS:        {"xxxx'W'wwe'T'HHmmssZ"},
+	STRICT_BASIC_WEEK_DATE_TIME_NO_MILLIS: {"xxxx'W'wwe'T'HHmmssZ"},
 
 	// A formatter for a full date as four digit year, two digit month of year, and two digit day of month: yyyy-MM-dd.
 	DATE:        {"yyyy-MM-dd"},
 	STRICT_DATE: {"yyyy-MM-dd"},
 
 	// A formatter that combines a full date and two digit hour of day: yyyy-MM-ddTHH.
-	DATE_HOUR:        {"yyyy-MM-ddTHH"},
-	STRICT_DATE_HOUR: {"yyyy-MM-ddTHH"},
+	DATE_HOUR:        {"yyyy-MM-dd'T'HH"},
+	STRICT_DATE_HOUR: {"yyyy-MM-dd'T'HH"},
 
 	// A formatter that combines a full date, two digit hour of day, and two digit minute of hour: yyyy-MM-ddTHH:mm.
-	DATE_HOUR_MINUTE:        {"yyyy-MM-ddTHH:mm"},
-	STRICT_DATE_HOUR_MINUTE: {"yyyy-MM-ddTHH:mm"},
+	DATE_HOUR_MINUTE:        {"yyyy-MM-dd'T'HH:mm"},
+	STRICT_DATE_HOUR_MINUTE: {"yyyy-MM-dd'T'HH:mm"},
 
 	// A formatter that combines a full date, two digit hour of day, two digit minute of hour, and two digit second of minute: yyyy-MM-ddTHH:mm:ss.
-	DATE_HOUR_MINUTE_SECOND:        {"yyyy-MM-ddTHH:mm:ss"},
-	STRICT_DATE_HOUR_MINUTE_SECOND: {"yyyy-MM-ddTHH:mm:ss"},
+	DATE_HOUR_MINUTE_SECOND:        {"yyyy-MM-dd'T'HH:mm:ss"},
+	STRICT_DATE_HOUR_MINUTE_SECOND: {"yyyy-MM-dd'T'HH:mm:ss"},
 
 	// A formatter that combines a full date, two digit hour of day, two digit minute of hour, two digit second of minute, and three digit fraction of second: yyyy-MM-ddTHH:mm:ss.SSS.
-	DATE_HOUR_MINUTE_SECOND_FRACTION:        {"yyyy-MM-ddTHH:mm:ss.SSS"},
-	STRICT_DATE_HOUR_MINUTE_SECOND_FRACTION: {"yyyy-MM-ddTHH:mm:ss.SSS"},
+	DATE_HOUR_MINUTE_SECOND_FRACTION:        {"yyyy-MM-dd'T'HH:mm:ss.SSS"},
+	STRICT_DATE_HOUR_MINUTE_SECOND_FRACTION: {"yyyy-MM-dd'T'HH:mm:ss.SSS"},
 
 	// A formatter that combines a full date, two digit hour of day, two digit minute of hour, two digit second of minute, and three digit fraction of second: yyyy-MM-ddTHH:mm:ss.SSS.
-	DATE_HOUR_MINUTE_SECOND_MILLIS:        {"yyyy-MM-ddTHH:mm:ss.SSS"},
-	STRICT_DATE_HOUR_MINUTE_SECOND_MILLIS: {"yyyy-MM-ddTHH:mm:ss.SSS"},
+	DATE_HOUR_MINUTE_SECOND_MILLIS:        {"yyyy-MM-dd'T'HH:mm:ss.SSS"},
+	STRICT_DATE_HOUR_MINUTE_SECOND_MILLIS: {"yyyy-MM-dd'T'HH:mm:ss.SSS"},
 
 	// A formatter that combines a full date and time, separated by a T: yyyy-MM-ddTHH:mm:ss.SSSZ.
-	DATE_TIME:        {"yyyy-MM-ddTHH:mm:ss.SSSZ"},
-	STRICT_DATE_TIME: {"yyyy-MM-ddTHH:mm:ss.SSSZ"},
+	DATE_TIME:        {"yyyy-MM-dd'T'HH:mm:ss.SSSZ"},
+	STRICT_DATE_TIME: {"yyyy-MM-dd'T'HH:mm:ss.SSSZ"},
 
 	// A formatter that combines a full date and time without millis, separated by a T: yyyy-MM-ddTHH:mm:ssZ.
-	DATE_TIME_NO_MILLIS:        {"yyyy-MM-ddTHH:mm:ssZ"},
-	STRICT_DATE_TIME_NO_MILLIS: {"yyyy-MM-ddTHH:mm:ssZ"},
+	DATE_TIME_NO_MILLIS:        {"yyyy-MM-dd'T'HH:mm:ssZ"},
+	STRICT_DATE_TIME_NO_MILLIS: {"yyyy-MM-dd'T'HH:mm:ssZ"},
 
 	// A formatter for a two digit hour of day: HH
 	HOUR:        {"HH"},
@@ -197,12 +218,12 @@ var BuiltInFormat = map[string][]string{
 	STRICT_ORDINAL_DATE: {"yyyy-DDD"},
 
 	// A formatter for a full ordinal date and time, using a four digit year and three digit dayOfYear: yyyy-DDDTHH:mm:ss.SSSZ.
-	ORDINAL_DATE_TIME:        {"yyyy-DDDTHH:mm:ss.SSSZ"},
-	STRICT_ORDINAL_DATE_TIME: {"yyyy-DDDTHH:mm:ss.SSSZ"},
+	ORDINAL_DATE_TIME:        {"yyyy-DDD'T'HH:mm:ss.SSSZ"},
+	STRICT_ORDINAL_DATE_TIME: {"yyyy-DDD'T'HH:mm:ss.SSSZ"},
 
 	// A formatter for a full ordinal date and time without millis, using a four digit year and three digit dayOfYear: yyyy-DDDTHH:mm:ssZ.
-	ORDINAL_DATE_TIME_NO_MILLIS:        {"yyyy-DDDTHH:mm:ssZ"},
-	STRICT_ORDINAL_DATE_TIME_NO_MILLIS: {"yyyy-DDDTHH:mm:ssZ"},
+	ORDINAL_DATE_TIME_NO_MILLIS:        {"yyyy-DDD'T'HH:mm:ssZ"},
+	STRICT_ORDINAL_DATE_TIME_NO_MILLIS: {"yyyy-DDD'T'HH:mm:ssZ"},
 
 	// A formatter for a two digit hour of day, two digit minute of hour, two digit second of minute, three digit fraction of second, and time zone offset: HH:mm:ss.SSSZ.
 	TIME:        {"HH:mm:ss.SSSZ"},
@@ -213,36 +234,36 @@ var BuiltInFormat = map[string][]string{
 	STRICT_TIME_NO_MILLIS: {"HH:mm:ssZ"},
 
 	// A formatter for a two digit hour of day, two digit minute of hour, two digit second of minute, three digit fraction of second, and time zone offset prefixed by T: THH:mm:ss.SSSZ.
-	T_TIME:        {"THH:mm:ss.SSSZ"},
-	STRICT_T_TIME: {"THH:mm:ss.SSSZ"},
+	T_TIME:        {"'T'HH:mm:ss.SSSZ"},
+	STRICT_T_TIME: {"'T'HH:mm:ss.SSSZ"},
 
 	// A formatter for a two digit hour of day, two digit minute of hour, two digit second of minute, and time zone offset prefixed by T: THH:mm:ssZ.
-	T_TIME_NO_MILLIS:        {"THH:mm:ssZ"},
-	STRICT_T_TIME_NO_MILLIS: {"THH:mm:ssZ"},
+	T_TIME_NO_MILLIS:        {"'T'HH:mm:ssZ"},
+	STRICT_T_TIME_NO_MILLIS: {"'T'HH:mm:ssZ"},
 
 	// A formatter for a full date as four digit weekyear, two digit week of weekyear, and one digit day of week: xxxx-Www-e.
-	WEEK_DATE:        {"xxxx-Www-e"},
-	STRICT_WEEK_DATE: {"xxxx-Www-e"},
+	WEEK_DATE:        {"xxxx-'W'ww-e"},
+	STRICT_WEEK_DATE: {"xxxx-'W'ww-e"},
 
 	// A formatter that combines a full weekyear date and time, separated by a T: xxxx-Www-eTHH:mm:ss.SSSZ.
-	WEEK_DATE_TIME:        {"xxxx-Www-eTHH:mm:ss.SSSZ"},
-	STRICT_WEEK_DATE_TIME: {"xxxx-Www-eTHH:mm:ss.SSSZ"},
+	WEEK_DATE_TIME:        {"xxxx-'W'ww-e'T'HH:mm:ss.SSSZ"},
+	STRICT_WEEK_DATE_TIME: {"xxxx-'W'ww-e'T'HH:mm:ss.SSSZ"},
 
 	// A formatter that combines a full weekyear date and time without millis, separated by a T: xxxx-Www-eTHH:mm:ssZ.
-	WEEK_DATE_TIME_NO_MILLIS:        {"xxxx-Www-eTHH:mm:ssZ"},
-	STRICT_WEEK_DATE_TIME_NO_MILLIS: {"xxxx-Www-eTHH:mm:ssZ"},
+	WEEK_DATE_TIME_NO_MILLIS:        {"xxxx-'W'ww-e'T'HH:mm:ssZ"},
+	STRICT_WEEK_DATE_TIME_NO_MILLIS: {"xxxx-'W'ww-e'T'HH:mm:ssZ"},
 
 	// A formatter for a four digit weekyear: xxxx.
 	WEEKYEAR:        {"xxxx"},
 	STRICT_WEEKYEAR: {"xxxx"},
 
 	// A formatter for a four digit weekyear and two digit week of weekyear: xxxx-Www.
-	WEEKYEAR_WEEK:        {"xxxx-Www"},
-	STRICT_WEEKYEAR_WEEK: {"xxxx-Www"},
+	WEEKYEAR_WEEK:        {"xxxx-'W'ww"},
+	STRICT_WEEKYEAR_WEEK: {"xxxx-'W'ww"},
 
 	// A formatter for a four digit weekyear, two digit week of weekyear, and one digit day of week: xxxx-Www-e.
-	WEEKYEAR_WEEK_DAY:        {"xxxx-Www-e"},
-	STRICT_WEEKYEAR_WEEK_DAY: {"xxxx-Www-e"},
+	WEEKYEAR_WEEK_DAY:        {"xxxx-'W'ww-e"},
+	STRICT_WEEKYEAR_WEEK_DAY: {"xxxx-'W'ww-e"},
 
 	// A formatter for a four digit year and two digit month of year: yyyy-MM.
 	YEAR_MONTH:        {"yyyy-MM"},
@@ -255,4 +276,50 @@ var BuiltInFormat = map[string][]string{
 	// A formatter for a four digit year, two digit month of year, and two digit day of month: yyyy-MM-dd.
 	YEAR_MONTH_DAY:        {"yyyy-MM-dd"},
 	STRICT_YEAR_MONTH_DAY: {"yyyy-MM-dd"},
+
+	// A formatter for internet date time of RFC 3339, fraction of second takes 1~9 digits and zone offset is required as Z or +HH:mm: yyyy-MM-ddTHH:mm:ss.SSSZZ.
+	RFC3339: {"java:uuuu-MM-dd'T'HH:mm:ss[.SSS]XXX"},
+	// A formatter like rfc3339, which prints nanoseconds: yyyy-MM-ddTHH:mm:ss.SSSSSSSSSZZ.
+	RFC3339_NANO: {"java:uuuu-MM-dd'T'HH:mm:ss[.SSSSSSSSS]XXX"},
+
+	// A formatter for date time of email header defined by RFC 2822, day of week and second are optional, zone is offset or abbreviation like GMT and EST: EEE, d MMM yyyy HH:mm:ss Z.
+	RFC2822: {"java:[EEE, ]d MMM yyyy HH:mm[:ss] Z", "java:[EEE, ]d MMM yyyy HH:mm[:ss] z"},
+
+	// A formatter for date time of RFC 1123, zone is offset or abbreviation like GMT: EEE, dd MMM yyyy HH:mm:ss z.
+	RFC1123: {"EEE, dd MMM yyyy HH:mm:ss z"},
+
+	// A formatter for HTTP-date of RFC 7231, which accepts IMF-fixdate, obsolete RFC 850 and asctime format, it prints IMF-fixdate: EEE, dd MMM yyyy HH:mm:ss z.
+	HTTP_DATE: {"EEE, dd MMM yyyy HH:mm:ss z", "EEEE, dd-MMM-yy HH:mm:ss z", "strftime:%a %b %e %H:%M:%S %Y"},
+
+	// A formatter for timestamp of BSD syslog defined by RFC 3164, which has no year, so the year is inferred from now: MMM ppd HH:mm:ss.
+	RFC3164: {"strftime:%b %e %H:%M:%S"},
+
+	// A formatter for timestamp of common log format used by access log of web server: dd/MMM/yyyy:HH:mm:ss Z.
+	COMMON_LOG: {"java:dd/MMM/yyyy:HH:mm:ss Z"},
+
+	// A formatter for date time of ISO 8601 basic format, fraction and zone offset are optional: yyyyMMddTHHmmss.SSSZ.
+	ISO8601_BASIC: {"yyyyMMdd'T'HHmmss[.SSS][Z]"},
+	// A formatter for date time of ISO 8601 extended format, fraction and zone offset are optional: yyyy-MM-ddTHH:mm:ss.SSSZZ.
+	ISO8601_EXTENDED: {"yyyy-MM-dd'T'HH:mm:ss[.SSS][ZZ]"},
+}
+
+// englishFormats are formats of internet standards, whose names of month and day of week are english in any locale.
+var englishFormats = map[string]bool{
+	RFC2822:    true,
+	RFC1123:    true,
+	HTTP_DATE:  true,
+	RFC3164:    true,
+	COMMON_LOG: true,
+}
+
+// builtInOptions returns options of patterns of built-in format name.
+func builtInOptions(name string) patternOptions {
+	var options = patternOptions{
+		fixedWidth:      strings.HasPrefix(name, "strict_"),
+		lenientFraction: name == RFC3339 || name == RFC3339_NANO,
+	}
+	if englishFormats[name] {
+		options.locale = englishLocale
+	}
+	return options
 }
@@ -0,0 +1,163 @@
+package datemath_parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateMathParser_internetFormat(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		expr    string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "TestDateMathParser_rfc3339_01", format: RFC3339, expr: "2021-05-10T10:20:30Z", want: time.Date(2021, 5, 10, 10, 20, 30, 0, time.UTC)},
+		{name: "TestDateMathParser_rfc3339_02", format: RFC3339, expr: "2021-05-10T10:20:30.5+08:00", want: time.Date(2021, 5, 10, 2, 20, 30, 500000000, time.UTC)},
+		{name: "TestDateMathParser_rfc3339_03", format: RFC3339_NANO, expr: "2021-05-10T10:20:30.123456789-03:30", want: time.Date(2021, 5, 10, 13, 50, 30, 123456789, time.UTC)},
+		{name: "TestDateMathParser_rfc3339_04", format: RFC3339, expr: "2021-05-10T10:20:30", wantErr: true},
+		{name: "TestDateMathParser_rfc3339_05", format: RFC3339, expr: "2021-05-10 10:20:30Z", wantErr: true},
+		{name: "TestDateMathParser_rfc3339_06", format: RFC3339, expr: "2021-05-10T10:20Z", wantErr: true},
+		{name: "TestDateMathParser_rfc3339_07", format: RFC3339, expr: "2021-02-29T10:20:30Z", wantErr: true},
+		{name: "TestDateMathParser_rfc3339_08", format: RFC3339_NANO, expr: "2021-05-10T10:20:30.1234567890Z", wantErr: true},
+		{name: "TestDateMathParser_rfc3339_09", format: RFC3339, expr: "2021-05-10T10:20:30+08", wantErr: true},
+		{name: "TestDateMathParser_rfc3339_10", format: RFC3339, expr: "2021-05-10T10:20:30+0800", wantErr: true},
+		{name: "TestDateMathParser_rfc3339_11", format: RFC3339, expr: "2021-05-10T10:20:30+02:00[Europe/Paris]", wantErr: true},
+		{name: "TestDateMathParser_rfc3339_12", format: RFC3339_NANO, expr: "2021-05-10T10:20:30.1+0800", wantErr: true},
+		{name: "TestDateMathParser_rfc3339_13", format: RFC3339, expr: "2021-05-10T10:20:30.123456-00:00", want: time.Date(2021, 5, 10, 10, 20, 30, 123456000, time.UTC)},
+		{name: "TestDateMathParser_rfc3339_14", format: RFC3339, expr: "2021-5-10T10:20:30Z", wantErr: true},
+
+		{name: "TestDateMathParser_rfc2822_01", format: RFC2822, expr: "Mon, 10 May 2021 10:20:30 +0200", want: time.Date(2021, 5, 10, 8, 20, 30, 0, time.UTC)},
+		{name: "TestDateMathParser_rfc2822_02", format: RFC2822, expr: "3 May 2021 10:20 GMT", want: time.Date(2021, 5, 3, 10, 20, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_rfc2822_03", format: RFC2822, expr: "Mon, 10 May 2021 10:20:30 EST", want: time.Date(2021, 5, 10, 15, 20, 30, 0, time.UTC)},
+		{name: "TestDateMathParser_rfc2822_04", format: RFC2822, expr: "Tue, 10 May 2021 10:20:30 +0200", wantErr: true},
+		{name: "TestDateMathParser_rfc2822_05", format: RFC2822, expr: "Mon, 10 May 2021 10:20:30", wantErr: true},
+
+		{name: "TestDateMathParser_rfc1123_01", format: RFC1123, expr: "Mon, 10 May 2021 10:20:30 GMT", want: time.Date(2021, 5, 10, 10, 20, 30, 0, time.UTC)},
+		{name: "TestDateMathParser_rfc1123_02", format: RFC1123, expr: "Mon, 10 May 2021 10:20:30 PDT", want: time.Date(2021, 5, 10, 17, 20, 30, 0, time.UTC)},
+		{name: "TestDateMathParser_rfc1123_03", format: RFC1123, expr: "Mon, 10 May 2021 10:20:30 XYZ", wantErr: true},
+
+		{name: "TestDateMathParser_httpDate01", format: HTTP_DATE, expr: "Sun, 06 Nov 1994 08:49:37 GMT", want: time.Date(1994, 11, 6, 8, 49, 37, 0, time.UTC)},
+		{name: "TestDateMathParser_httpDate02", format: HTTP_DATE, expr: "Sunday, 06-Nov-94 08:49:37 GMT", want: time.Date(1994, 11, 6, 8, 49, 37, 0, time.UTC)},
+		{name: "TestDateMathParser_httpDate03", format: HTTP_DATE, expr: "Sun Nov  6 08:49:37 1994", want: time.Date(1994, 11, 6, 8, 49, 37, 0, time.UTC)},
+		{name: "TestDateMathParser_httpDate04", format: HTTP_DATE, expr: "Sun, 6 Nov 1994 08:49:37", wantErr: true},
+
+		{name: "TestDateMathParser_commonLog01", format: COMMON_LOG, expr: "10/Oct/2000:13:55:36 -0700", want: time.Date(2000, 10, 10, 20, 55, 36, 0, time.UTC)},
+		{name: "TestDateMathParser_commonLog02", format: COMMON_LOG, expr: "10/Oct/2000 13:55:36 -0700", wantErr: true},
+		{name: "TestDateMathParser_commonLog03", format: COMMON_LOG, expr: "10/Oct/2000:13:55:36", wantErr: true},
+
+		{name: "TestDateMathParser_iso8601_01", format: ISO8601_BASIC, expr: "20210510T102030Z", want: time.Date(2021, 5, 10, 10, 20, 30, 0, time.UTC)},
+		{name: "TestDateMathParser_iso8601_02", format: ISO8601_BASIC, expr: "20210510T102030.123+0800", want: time.Date(2021, 5, 10, 2, 20, 30, 123000000, time.UTC)},
+		{name: "TestDateMathParser_iso8601_03", format: ISO8601_EXTENDED, expr: "2021-05-10T10:20:30", want: time.Date(2021, 5, 10, 10, 20, 30, 0, time.UTC)},
+		{name: "TestDateMathParser_iso8601_04", format: ISO8601_EXTENDED, expr: "2021-05-10T10:20:30.123-05:00", want: time.Date(2021, 5, 10, 15, 20, 30, 123000000, time.UTC)},
+		{name: "TestDateMathParser_iso8601_05", format: ISO8601_BASIC, expr: "2021-05-10T10:20:30Z", wantErr: true},
+		{name: "TestDateMathParser_iso8601_06", format: ISO8601_EXTENDED, expr: "20210510T102030Z", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p, err = NewDateMathParser(WithFormat([]string{tt.format}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got, perr = p.Parse(tt.expr)
+			if (perr != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", perr, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDateMathParser_internetFormatFormat(t *testing.T) {
+	var tim = time.Date(1994, 11, 6, 8, 49, 37, 123456789, time.UTC)
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{name: "TestDateMathParser_internetFormatFormat01", format: RFC3339, want: "1994-11-06T08:49:37.123Z"},
+		{name: "TestDateMathParser_internetFormatFormat02", format: RFC3339_NANO, want: "1994-11-06T08:49:37.123456789Z"},
+		{name: "TestDateMathParser_internetFormatFormat03", format: RFC2822, want: "Sun, 6 Nov 1994 08:49:37 +0000"},
+		{name: "TestDateMathParser_internetFormatFormat04", format: HTTP_DATE, want: "Sun, 06 Nov 1994 08:49:37 GMT"},
+		{name: "TestDateMathParser_internetFormatFormat05", format: RFC3164, want: "Nov  6 08:49:37"},
+		{name: "TestDateMathParser_internetFormatFormat06", format: COMMON_LOG, want: "06/Nov/1994:08:49:37 +0000"},
+		{name: "TestDateMathParser_internetFormatFormat07", format: ISO8601_BASIC, want: "19941106T084937.123Z"},
+		{name: "TestDateMathParser_internetFormatFormat08", format: ISO8601_EXTENDED, want: "1994-11-06T08:49:37.123Z"},
+	}
+	var p, _ = NewDateMathParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := p.Format(tim, tt.format); err != nil || got != tt.want {
+				t.Errorf("Format() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestDateMathParser_internetFormatLocale(t *testing.T) {
+	var tim = time.Date(2021, 1, 5, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name   string
+		locale string
+		format string
+		expr   string
+		want   time.Time
+	}{
+		{name: "TestDateMathParser_internetFormatLocale01", locale: "de-DE", format: RFC2822, expr: "Mon, 10 May 2021 10:20:30 +0200", want: time.Date(2021, 5, 10, 8, 20, 30, 0, time.UTC)},
+		{name: "TestDateMathParser_internetFormatLocale02", locale: "de-DE", format: HTTP_DATE, expr: "Sun, 06 Nov 1994 08:49:37 GMT", want: time.Date(1994, 11, 6, 8, 49, 37, 0, time.UTC)},
+		{name: "TestDateMathParser_internetFormatLocale03", locale: "de-DE", format: COMMON_LOG, expr: "10/Oct/2000:13:55:36 -0700", want: time.Date(2000, 10, 10, 20, 55, 36, 0, time.UTC)},
+		{name: "TestDateMathParser_internetFormatLocale04", locale: "fr-FR", format: RFC1123, expr: "Tue, 05 Jan 2021 03:04:05 GMT", want: tim},
+		{name: "TestDateMathParser_internetFormatLocale05", locale: "fr-FR", format: HTTP_DATE, expr: "Tue Jan  5 03:04:05 2021", want: tim},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p, err = NewDateMathParser(WithLocale(tt.locale), WithFormat([]string{tt.format}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, err := p.Parse(tt.expr); err != nil || !got.Equal(tt.want) {
+				t.Errorf("Parse() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+
+	var formats = map[string]string{
+		HTTP_DATE:  "Tue, 05 Jan 2021 03:04:05 GMT",
+		RFC2822:    "Tue, 5 Jan 2021 03:04:05 +0000",
+		RFC3164:    "Jan  5 03:04:05",
+		COMMON_LOG: "05/Jan/2021:03:04:05 +0000",
+	}
+	var registry = NewFormatRegistry()
+	_ = registry.Alias("http", HTTP_DATE)
+	for _, opts := range [][]DateMathParserOption{{WithLocale("fr-FR")}, {WithLocale("fr-FR"), WithRegistry(registry)}} {
+		var p, _ = NewDateMathParser(opts...)
+		for format, want := range formats {
+			if got, err := p.Format(tim, format); err != nil || got != want {
+				t.Errorf("Format(%s) = %q, %v, want %q", format, got, err, want)
+			}
+		}
+	}
+	var p, _ = NewDateMathParser(WithLocale("fr-FR"), WithRegistry(registry), WithFormat([]string{"http"}))
+	if got, err := p.Parse("Tue, 05 Jan 2021 03:04:05 GMT"); err != nil || !got.Equal(tim) {
+		t.Errorf("Parse() = %v, %v, want %v", got, err, tim)
+	}
+}
+
+func TestBuiltInFormat_dialect(t *testing.T) {
+	for name, patterns := range BuiltInFormat {
+		for _, pattern := range patterns {
+			if pattern == EPOCH_SECOND || pattern == EPOCH_MILLIS || pattern == EPOCH_AUTO {
+				continue
+			}
+			// patterns without prefix are joda syntax, whatever dialect of parser
+			var source, dialect = splitPatternDialect(pattern, PatternJoda)
+			if _, err := compilePattern(source, dialect); err != nil {
+				t.Errorf("pattern %s of %s is invalid: %v", pattern, name, err)
+			}
+		}
+	}
+}
@@ -4,5 +4,4 @@ go 1.14
 
 require (
 	github.com/araddon/dateparse v0.0.0-20210429162001-6b43995a97de
-	github.com/vjeantet/jodaTime v1.0.0
 )
@@ -0,0 +1,118 @@
+package datemath_parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Dialect is syntax of date math expression, which differs from PatternDialect selecting syntax of format pattern.
+type Dialect int
+
+const (
+	// DialectElasticSearch is date math of ElasticSearch, which is the default.
+	DialectElasticSearch Dialect = iota
+	// DialectGrafana is relative time of Grafana like `now-7d/d`, units are added by calendar,
+	// `Q` is calendar quarter, rounding takes no count and anchor isn't rounded by its precision.
+	DialectGrafana
+	// DialectSplunk is relative time modifier of Splunk like `-7d@d`, units are added by calendar,
+	// snap `@unit` always rounds down and `@w0`~`@w6` snaps to the last sunday~saturday.
+	DialectSplunk
+)
+
+func (d Dialect) String() string {
+	switch d {
+	case DialectElasticSearch:
+		return "elasticsearch"
+	case DialectGrafana:
+		return "grafana"
+	case DialectSplunk:
+		return "splunk"
+	}
+	return "Dialect(" + strconv.Itoa(int(d)) + ")"
+}
+
+var grafanaDurRegexp = regexp.MustCompile(`^([\+-]\d*|\/)(fy|fQ|y|Q|M|w|d|h|m|s)`)
+
+// parseGrafana parses expression like dateMath.parse of Grafana, anchor without `now` is
+// absolute time, optionally followed by `||` and math.
+func (p *DateMathParser) parseGrafana(expr string) (Result, error) {
+	var res = Result{}
+	var dur = ""
+	if strings.HasPrefix(expr, "now") {
+		dur = expr[3:]
+		res.Anchor = p.now()
+		res.IsNow = true
+		res.Precision = PrecisionNanosecond
+	} else {
+		var anchor = expr
+		if sep := strings.Index(expr, "||"); sep != -1 {
+			anchor, dur = expr[:sep], expr[sep+2:]
+		}
+		var err error
+		if res.Anchor, res.Format, res.Precision, err = p.parseGrafanaAnchor(anchor); err != nil {
+			return Result{Time: emptyTime, Anchor: emptyTime}, err
+		}
+	}
+	res.Anchor = res.Anchor.UTC()
+	res.Time = res.Anchor
+	var err error
+	if res.Ops, err = parseGrafanaDur(dur); err != nil {
+		return Result{Time: emptyTime, Anchor: emptyTime}, err
+	}
+	if res.Time, err = p.applyOps(res.Ops, res.Anchor); err != nil {
+		return Result{Time: emptyTime, Anchor: emptyTime}, err
+	}
+	return res, nil
+}
+
+// parseGrafanaAnchor parses absolute time of Grafana, without formats digits are epoch
+// milliseconds like `from=1620640800000` of dashboard url, and 8 digits are date like `20210510`.
+func (p *DateMathParser) parseGrafanaAnchor(expr string) (time.Time, string, Precision, error) {
+	if len(p.Formats) != 0 || expr == "" || strings.TrimFunc(expr, unicode.IsDigit) != "" {
+		return p.parseAnchor(expr)
+	}
+	if len(expr) == 8 {
+		var tim, err = time.ParseInLocation("20060102", expr, p.location())
+		if err != nil {
+			return emptyTime, "", PrecisionUnknown, fmt.Errorf("date %s is invalid", expr)
+		}
+		return tim, BASIC_DATE, PrecisionDay, nil
+	}
+	var millis, err = strconv.ParseInt(expr, 10, 64)
+	if err != nil {
+		return emptyTime, "", PrecisionUnknown, fmt.Errorf("epoch %s is out of range", expr)
+	}
+	tim, err := epochTime(millis/1000, millis%1000*int64(time.Millisecond))
+	return tim, EPOCH_MILLIS, PrecisionMillisecond, err
+}
+
+// parseGrafanaDur parses math of Grafana, which ignores white spaces and fails on any other
+// character, rounding like `/d` takes no count.
+func parseGrafanaDur(dur string) ([]MathOp, error) {
+	dur = strings.Join(strings.Fields(dur), "")
+	var ops []MathOp
+	for dur != "" {
+		var s = grafanaDurRegexp.FindStringSubmatch(dur)
+		if s == nil {
+			return nil, fmt.Errorf(`expect match expression: ([\+-]\d*|\/)(fy|fQ|y|Q|M|w|d|h|m|s), got %s`, dur)
+		}
+		dur = dur[len(s[0]):]
+		if s[1] == "/" {
+			ops = append(ops, MathOp{Op: '/', Unit: s[2]})
+			continue
+		}
+		var d = 1
+		if len(s[1]) > 1 {
+			var err error
+			if d, err = strconv.Atoi(s[1][1:]); err != nil {
+				return nil, &RangeError{Op: s[0], Reason: "amount overflows int"}
+			}
+		}
+		ops = append(ops, MathOp{Op: s[1][0], Amount: d, Unit: s[2]})
+	}
+	return ops, nil
+}
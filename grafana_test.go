@@ -0,0 +1,58 @@
+package datemath_parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateMathParser_grafana(t *testing.T) {
+	// 2021-05-10 is monday
+	var now = time.Date(2021, 5, 10, 10, 37, 42, 0, time.UTC)
+	tests := []struct {
+		name     string
+		opts     []DateMathParserOption
+		from, to string
+		wantFrom time.Time
+		wantTo   time.Time
+		wantErr  bool
+	}{
+		{name: "TestDateMathParser_grafana01", from: "now-6h", to: "now", wantFrom: time.Date(2021, 5, 10, 4, 37, 42, 0, time.UTC), wantTo: now},
+		{name: "TestDateMathParser_grafana02", from: "now-1d/d", to: "now-1d/d", wantFrom: time.Date(2021, 5, 9, 0, 0, 0, 0, time.UTC), wantTo: time.Date(2021, 5, 9, 23, 59, 59, 999000000, time.UTC)},
+		{name: "TestDateMathParser_grafana03", opts: []DateMathParserOption{WithFiscalYearStartMonth(time.April)}, from: "now/fy", to: "now/fy", wantFrom: time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC), wantTo: time.Date(2022, 3, 31, 23, 59, 59, 999000000, time.UTC)},
+		{name: "TestDateMathParser_grafana04", from: "now/Q", to: "now/Q", wantFrom: time.Date(2021, 4, 1, 0, 0, 0, 0, time.UTC), wantTo: time.Date(2021, 6, 30, 23, 59, 59, 999000000, time.UTC)},
+		{name: "TestDateMathParser_grafana05", from: "now-1w/w", to: "now-1w/w", wantFrom: time.Date(2021, 5, 3, 0, 0, 0, 0, time.UTC), wantTo: time.Date(2021, 5, 9, 23, 59, 59, 999000000, time.UTC)},
+		{name: "TestDateMathParser_grafana06", from: "2021-03-31||-1M", to: "2021-03-31||+1y", wantFrom: time.Date(2021, 2, 28, 0, 0, 0, 0, time.UTC), wantTo: time.Date(2022, 3, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_grafana07", from: "1620640800000", to: "1620644400000", wantFrom: time.Date(2021, 5, 10, 10, 0, 0, 0, time.UTC), wantTo: time.Date(2021, 5, 10, 11, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_grafana08", opts: []DateMathParserOption{WithTimeZone("+08:00")}, from: "20210510", to: "now/d", wantFrom: time.Date(2021, 5, 9, 16, 0, 0, 0, time.UTC), wantTo: time.Date(2021, 5, 10, 15, 59, 59, 999000000, time.UTC)},
+		{name: "TestDateMathParser_grafana09", opts: []DateMathParserOption{WithFormat([]string{"year_month"})}, from: "2021-05", to: "2021-05", wantFrom: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC), wantTo: time.Date(2021, 5, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_grafana10", opts: []DateMathParserOption{WithTimeZone("America/New_York")}, from: "2021-03-13T12:00:00||+1d", to: "now - 2 d", wantFrom: time.Date(2021, 3, 14, 16, 0, 0, 0, time.UTC), wantTo: time.Date(2021, 5, 8, 10, 37, 42, 0, time.UTC)},
+		{name: "TestDateMathParser_grafana11", from: "now-d", to: "now+Q", wantFrom: time.Date(2021, 5, 9, 10, 37, 42, 0, time.UTC), wantTo: time.Date(2021, 8, 10, 10, 37, 42, 0, time.UTC)},
+		{name: "TestDateMathParser_grafana12", from: "now-1d/d junk", to: "now", wantErr: true},
+		{name: "TestDateMathParser_grafana13", from: "now/15m", to: "now", wantErr: true},
+		{name: "TestDateMathParser_grafana14", from: "now-1bd", to: "now", wantErr: true},
+		{name: "TestDateMathParser_grafana15", from: "now", to: "now-1H", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts = append([]DateMathParserOption{WithNow(func() time.Time { return now }), WithDialect(DialectGrafana)}, tt.opts...)
+			var p, err = NewDateMathParser(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var from, to, perr = p.ParseRange(tt.from, tt.to)
+			if (perr != nil) != tt.wantErr {
+				t.Errorf("ParseRange() error = %v, wantErr %v", perr, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && (!from.Equal(tt.wantFrom) || !to.Equal(tt.wantTo)) {
+				t.Errorf("ParseRange() = %v, %v, want %v, %v", from, to, tt.wantFrom, tt.wantTo)
+			}
+		})
+	}
+	if _, err := NewDateMathParser(WithDialect(Dialect(5))); err == nil {
+		t.Errorf("expect error for unknown dialect")
+	}
+	if DialectGrafana.String() != "grafana" || Dialect(5).String() != "Dialect(5)" {
+		t.Errorf("String() = %s, %s", DialectGrafana, Dialect(5))
+	}
+}
@@ -0,0 +1,241 @@
+package datemath_parser
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// yearlyHoliday is all-day event recurring every interval years, like `RRULE:FREQ=YEARLY`.
+type yearlyHoliday struct {
+	month    time.Month
+	day      int
+	days     int // length of event in days
+	from     int // year of the first occurrence
+	until    int // year of the last occurrence, 0 means no limit
+	interval int
+	excluded map[string]bool // start dates of excluded occurrences by EXDATE
+}
+
+// contains reports whether date is in one of occurrences.
+func (y yearlyHoliday) contains(date time.Time) bool {
+	var year, month, day = date.Date()
+	for i := 0; i < y.days; i++ {
+		var start = time.Date(year, month, day-i, 0, 0, 0, 0, time.UTC)
+		if start.Month() != y.month || start.Day() != y.day {
+			continue
+		}
+		if start.Year() < y.from || (y.until != 0 && start.Year() > y.until) || (start.Year()-y.from)%y.interval != 0 {
+			continue
+		}
+		if !y.excluded[start.Format("2006-01-02")] {
+			return true
+		}
+	}
+	return false
+}
+
+// icsEvent holds properties of VEVENT which decide holidays.
+type icsEvent struct {
+	start, end time.Time
+	allDay     bool
+	rrule      string
+	excluded   []time.Time
+	cancelled  bool
+	summary    string
+}
+
+// unsupportedRule reports RRULE which isn't yearly recurrence on the date of DTSTART,
+// like `FREQ=MONTHLY` or `BYDAY=-1MO`, event of such rule is skipped.
+type unsupportedRule struct {
+	rule, reason string
+}
+
+func (e *unsupportedRule) Error() string {
+	return fmt.Sprintf("ics: RRULE %s is not supported, %s", e.rule, e.reason)
+}
+
+// Skipped returns events skipped by LoadICSCalendar for unsupported recurrence, each of them
+// is summary of event followed by the reason, so holidays like memorial day can be added by hand.
+func (h *HolidayList) Skipped() []string {
+	return append([]string{}, h.skipped...)
+}
+
+// LoadICSCalendar reads holidays from all-day VEVENT of iCalendar (.ics) file exported by
+// calendar applications, events lasting several days and yearly recurrence of RRULE are
+// supported, events with time are not holidays. Days of weekend are not business days either.
+// Events of other recurrence are skipped instead of failing the whole file, see Skipped.
+func LoadICSCalendar(r io.Reader) (*HolidayList, error) {
+	var lines, err = unfoldICS(r)
+	if err != nil {
+		return nil, err
+	}
+	var h = NewHolidayList()
+	var event *icsEvent
+	for _, line := range lines {
+		var name, params, value = splitICSProperty(line)
+		switch {
+		case name == "BEGIN" && value == "VEVENT":
+			event = &icsEvent{}
+		case name == "END" && value == "VEVENT":
+			if event == nil {
+				return nil, fmt.Errorf("ics: END:VEVENT is not paired with BEGIN:VEVENT")
+			}
+			if err := h.addEvent(event); err != nil {
+				var unsupported, ok = err.(*unsupportedRule)
+				if !ok {
+					return nil, err
+				}
+				h.skipped = append(h.skipped, fmt.Sprintf("%s: %s", event.summary, unsupported))
+			}
+			event = nil
+		case event == nil:
+			continue
+		case name == "DTSTART":
+			if event.start, event.allDay, err = parseICSDate(params, value); err != nil {
+				return nil, err
+			}
+		case name == "DTEND":
+			if event.end, _, err = parseICSDate(params, value); err != nil {
+				return nil, err
+			}
+		case name == "RRULE":
+			event.rrule = value
+		case name == "EXDATE":
+			for _, v := range strings.Split(value, ",") {
+				var date, _, err = parseICSDate(params, v)
+				if err != nil {
+					return nil, err
+				}
+				event.excluded = append(event.excluded, date)
+			}
+		case name == "STATUS":
+			event.cancelled = value == "CANCELLED"
+		case name == "SUMMARY":
+			event.summary = value
+		}
+	}
+	if event != nil {
+		return nil, fmt.Errorf("ics: BEGIN:VEVENT is not closed")
+	}
+	return h, nil
+}
+
+// unfoldICS joins folded lines, which continue previous line when starting with space or tab.
+func unfoldICS(r io.Reader) ([]string, error) {
+	var lines = []string{}
+	var scanner = bufio.NewScanner(r)
+	for scanner.Scan() {
+		var line = strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) != 0 {
+			lines[len(lines)-1] += line[1:]
+		} else if line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, scanner.Err()
+}
+
+// splitICSProperty splits line like `DTSTART;VALUE=DATE:20211224` into name, params and value.
+func splitICSProperty(line string) (string, string, string) {
+	var colon = strings.Index(line, ":")
+	if colon == -1 {
+		return strings.ToUpper(line), "", ""
+	}
+	var name, params = line[:colon], ""
+	if i := strings.Index(name, ";"); i != -1 {
+		name, params = name[:i], name[i+1:]
+	}
+	return strings.ToUpper(name), strings.ToUpper(params), strings.TrimSpace(line[colon+1:])
+}
+
+// parseICSDate parses date like `20211224`, and date time like `20211224T100000Z` which isn't all day.
+func parseICSDate(params, value string) (time.Time, bool, error) {
+	if strings.Contains(params, "VALUE=DATE") && !strings.Contains(params, "VALUE=DATE-TIME") || len(value) == 8 {
+		var date, err = time.Parse("20060102", value)
+		if err != nil {
+			return emptyTime, false, fmt.Errorf("ics: date %s is invalid", value)
+		}
+		return date, true, nil
+	}
+	var date, err = time.Parse("20060102T150405", strings.TrimSuffix(value, "Z"))
+	if err != nil {
+		return emptyTime, false, fmt.Errorf("ics: date time %s is invalid", value)
+	}
+	return date, false, nil
+}
+
+func (h *HolidayList) addEvent(e *icsEvent) error {
+	if !e.allDay || e.cancelled {
+		return nil
+	}
+	var days = 1
+	if !e.end.IsZero() {
+		// DTEND of all-day event is exclusive
+		days = int(e.end.Sub(e.start).Hours() / 24)
+		if days < 1 {
+			days = 1
+		}
+	}
+	if e.rrule == "" {
+		for i := 0; i < days; i++ {
+			h.Add(e.start.AddDate(0, 0, i))
+		}
+		return nil
+	}
+	var y = yearlyHoliday{month: e.start.Month(), day: e.start.Day(), days: days, from: e.start.Year(), interval: 1, excluded: map[string]bool{}}
+	for _, date := range e.excluded {
+		y.excluded[date.Format("2006-01-02")] = true
+	}
+	for _, part := range strings.Split(e.rrule, ";") {
+		var kv = strings.SplitN(part, "=", 2)
+		if len(kv) != 2 {
+			return fmt.Errorf("ics: RRULE %s is invalid", e.rrule)
+		}
+		var key, value = strings.ToUpper(kv[0]), kv[1]
+		switch key {
+		case "FREQ":
+			if strings.ToUpper(value) != "YEARLY" {
+				return &unsupportedRule{rule: e.rrule, reason: "only FREQ=YEARLY is supported"}
+			}
+		case "INTERVAL", "COUNT":
+			var n, err = strconv.Atoi(value)
+			if err != nil || n < 1 {
+				return fmt.Errorf("ics: RRULE %s is invalid", e.rrule)
+			}
+			if key == "INTERVAL" {
+				y.interval = n
+			} else {
+				y.until = -n // resolved after interval is known
+			}
+		case "UNTIL":
+			var until, _, err = parseICSDate("", value)
+			if err != nil {
+				return err
+			}
+			y.until = until.Year()
+			if until.Before(time.Date(until.Year(), y.month, y.day, 0, 0, 0, 0, time.UTC)) {
+				y.until--
+			}
+		case "BYMONTH":
+			if value != strconv.Itoa(int(y.month)) {
+				return &unsupportedRule{rule: e.rrule, reason: "BYMONTH should be month of DTSTART"}
+			}
+		case "BYMONTHDAY":
+			if value != strconv.Itoa(y.day) {
+				return &unsupportedRule{rule: e.rrule, reason: "BYMONTHDAY should be day of DTSTART"}
+			}
+		case "WKST":
+		default:
+			return &unsupportedRule{rule: e.rrule, reason: key + " is not supported"}
+		}
+	}
+	if y.until < 0 {
+		y.until = y.from + (-y.until-1)*y.interval
+	}
+	h.yearly = append(h.yearly, y)
+	return nil
+}
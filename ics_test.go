@@ -0,0 +1,117 @@
+package datemath_parser
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLoadICSCalendar(t *testing.T) {
+	var ics = strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20211224",
+		"DTEND;VALUE=DATE:20211225",
+		"SUMMARY:Christmas Eve",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20200101",
+		"RRULE:FREQ=YEARLY;UNTIL=20231231",
+		"EXDATE;VALUE=DATE:20220101",
+		"SUMMARY:New Year's",
+		"  Day",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20190506",
+		"DTEND;VALUE=DATE:20190508",
+		"RRULE:FREQ=YEARLY;COUNT=3",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART:20211228T100000Z",
+		"DTEND:20211228T110000Z",
+		"SUMMARY:Meeting",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+	var h, err = LoadICSCalendar(strings.NewReader(ics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name string
+		date time.Time
+		want bool
+	}{
+		{name: "TestLoadICSCalendar01", date: time.Date(2021, 12, 24, 0, 0, 0, 0, time.UTC), want: false},
+		{name: "TestLoadICSCalendar02", date: time.Date(2021, 12, 23, 0, 0, 0, 0, time.UTC), want: true},
+		{name: "TestLoadICSCalendar03", date: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC), want: false},
+		{name: "TestLoadICSCalendar04", date: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC), want: true},
+		{name: "TestLoadICSCalendar05", date: time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC), want: true},
+		{name: "TestLoadICSCalendar06", date: time.Date(2021, 5, 7, 0, 0, 0, 0, time.UTC), want: false},
+		{name: "TestLoadICSCalendar07", date: time.Date(2021, 5, 6, 0, 0, 0, 0, time.UTC), want: false},
+		{name: "TestLoadICSCalendar08", date: time.Date(2022, 5, 6, 0, 0, 0, 0, time.UTC), want: true},
+		{name: "TestLoadICSCalendar09", date: time.Date(2021, 12, 28, 0, 0, 0, 0, time.UTC), want: true},
+		{name: "TestLoadICSCalendar10", date: time.Date(2021, 12, 25, 0, 0, 0, 0, time.UTC), want: false},
+		{name: "TestLoadICSCalendar11", date: time.Date(2022, 1, 3, 0, 0, 0, 0, time.UTC), want: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := h.IsBusinessDay(tt.date); got != tt.want {
+				t.Errorf("IsBusinessDay(%v) = %v, want %v", tt.date, got, tt.want)
+			}
+		})
+	}
+
+	var p, _ = NewDateMathParser(WithCalendar(h), WithNow(func() time.Time { return time.Date(2021, 12, 27, 10, 0, 0, 0, time.UTC) }))
+	if got, err := p.Parse("now-1bd"); err != nil || !got.Equal(time.Date(2021, 12, 23, 10, 0, 0, 0, time.UTC)) {
+		t.Errorf("Parse() = %v, %v", got, err)
+	}
+
+	for _, bad := range []string{
+		"BEGIN:VEVENT\nDTSTART;VALUE=DATE:2021122\nEND:VEVENT",
+		"BEGIN:VEVENT\nDTSTART;VALUE=DATE:20211224\nRRULE:FREQ=YEARLY;COUNT=x\nEND:VEVENT",
+		"BEGIN:VEVENT\nDTSTART;VALUE=DATE:20211224",
+	} {
+		if _, err := LoadICSCalendar(strings.NewReader(bad)); err == nil {
+			t.Errorf("expect error for %q", bad)
+		}
+	}
+}
+
+func TestLoadICSCalendar_skipped(t *testing.T) {
+	var ics = strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20210531",
+		"RRULE:FREQ=YEARLY;BYMONTH=5;BYDAY=-1MO",
+		"SUMMARY:Memorial Day",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20210101",
+		"RRULE:FREQ=MONTHLY",
+		"SUMMARY:Month Start",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20210705",
+		"SUMMARY:Independence Day (observed)",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\n")
+	var h, err = LoadICSCalendar(strings.NewReader(ics))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h.IsBusinessDay(time.Date(2021, 7, 5, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("2021-07-05 should be holiday")
+	}
+	for _, date := range []time.Time{time.Date(2021, 5, 31, 0, 0, 0, 0, time.UTC), time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)} {
+		if !h.IsBusinessDay(date) {
+			t.Errorf("%v of skipped event should be business day", date)
+		}
+	}
+	var skipped = h.Skipped()
+	if len(skipped) != 2 || !strings.HasPrefix(skipped[0], "Memorial Day: ") || !strings.Contains(skipped[0], "BYDAY") || !strings.HasPrefix(skipped[1], "Month Start: ") {
+		t.Errorf("Skipped() = %q", skipped)
+	}
+}
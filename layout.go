@@ -0,0 +1,46 @@
+package datemath_parser
+
+import (
+	"fmt"
+	"time"
+)
+
+// goLayouts are layout constants of go time package, which can be used by name like `go:RFC3339`.
+var goLayouts = map[string]string{
+	"ANSIC":       time.ANSIC,
+	"UnixDate":    time.UnixDate,
+	"RubyDate":    time.RubyDate,
+	"RFC822":      time.RFC822,
+	"RFC822Z":     time.RFC822Z,
+	"RFC850":      time.RFC850,
+	"RFC1123":     time.RFC1123,
+	"RFC1123Z":    time.RFC1123Z,
+	"RFC3339":     time.RFC3339,
+	"RFC3339Nano": time.RFC3339Nano,
+	"Kitchen":     time.Kitchen,
+	"Stamp":       time.Stamp,
+	"StampMilli":  time.StampMilli,
+	"StampMicro":  time.StampMicro,
+	"StampNano":   time.StampNano,
+	"DateTime":    "2006-01-02 15:04:05",
+	"DateOnly":    "2006-01-02",
+	"TimeOnly":    "15:04:05",
+}
+
+// goLayout returns layout of constant name, or source itself.
+func goLayout(source string) string {
+	if layout, ok := goLayouts[source]; ok {
+		return layout
+	}
+	return source
+}
+
+// parseLayout parses value with go time layout, names of go layout are always english.
+func (p *pattern) parseLayout(value string, ctx *parseContext) (time.Time, Precision, error) {
+	var layout = goLayout(p.source)
+	var tim, err = time.ParseInLocation(layout, value, ctx.loc)
+	if err != nil {
+		return emptyTime, PrecisionUnknown, fmt.Errorf("failed to parse %s with pattern: %s, %s", value, p.source, err)
+	}
+	return tim, layoutPrecision(layout), nil
+}
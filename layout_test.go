@@ -0,0 +1,91 @@
+package datemath_parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateMathParser_goLayout(t *testing.T) {
+	tests := []struct {
+		name          string
+		opts          []DateMathParserOption
+		expr          string
+		want          time.Time
+		wantPrecision Precision
+		wantErr       bool
+	}{
+		{
+			name:          "TestDateMathParser_goLayout01",
+			opts:          []DateMathParserOption{WithFormat([]string{"go:2006-01-02 15:04:05"})},
+			expr:          "2021-05-10 10:20:30",
+			want:          time.Date(2021, 5, 10, 10, 20, 30, 0, time.UTC),
+			wantPrecision: PrecisionSecond,
+		},
+		{
+			name:          "TestDateMathParser_goLayout02",
+			opts:          []DateMathParserOption{WithFormat([]string{"go:RFC3339"})},
+			expr:          "2021-05-10T10:20:30+08:00",
+			want:          time.Date(2021, 5, 10, 2, 20, 30, 0, time.UTC),
+			wantPrecision: PrecisionSecond,
+		},
+		{
+			name:          "TestDateMathParser_goLayout03",
+			opts:          []DateMathParserOption{WithFormat([]string{"go:" + time.RFC3339Nano})},
+			expr:          "2021-05-10T10:20:30.123456789Z",
+			want:          time.Date(2021, 5, 10, 10, 20, 30, 123456789, time.UTC),
+			wantPrecision: PrecisionNanosecond,
+		},
+		{
+			name:          "TestDateMathParser_goLayout04",
+			opts:          []DateMathParserOption{WithPatternDialect(PatternGo), WithTimeZone("+08:00"), WithFormat([]string{"Jan 2, 2006"})},
+			expr:          "May 10, 2021",
+			want:          time.Date(2021, 5, 9, 16, 0, 0, 0, time.UTC),
+			wantPrecision: PrecisionDay,
+		},
+		{
+			name:    "TestDateMathParser_goLayout05",
+			opts:    []DateMathParserOption{WithFormat([]string{"go:2006-01-02"})},
+			expr:    "2021-13-10",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p, err = NewDateMathParser(tt.opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var res, perr = p.ParseDetailed(tt.expr)
+			if (perr != nil) != tt.wantErr {
+				t.Errorf("ParseDetailed() error = %v, wantErr %v", perr, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && (!res.Time.Equal(tt.want) || res.Precision != tt.wantPrecision) {
+				t.Errorf("ParseDetailed() = %v %v, want %v %v", res.Time, res.Precision, tt.want, tt.wantPrecision)
+			}
+		})
+	}
+}
+
+func TestDateMathParser_formatDialect(t *testing.T) {
+	var tim = time.Date(2021, 5, 3, 9, 5, 7, 123456000, time.UTC)
+	tests := []struct {
+		name   string
+		format string
+		want   string
+	}{
+		{name: "TestDateMathParser_formatDialect01", format: "go:2006-01-02 15:04:05.000", want: "2021-05-03 17:05:07.123"},
+		{name: "TestDateMathParser_formatDialect02", format: "go:RFC3339", want: "2021-05-03T17:05:07+08:00"},
+		{name: "TestDateMathParser_formatDialect03", format: "strftime:%Y-%m-%d %H:%M:%S.%f%z", want: "2021-05-03 17:05:07.123456+0800"},
+		{name: "TestDateMathParser_formatDialect04", format: "strftime:%a %b %e %T %Y", want: "Mon May  3 17:05:07 2021"},
+		{name: "TestDateMathParser_formatDialect05", format: "strftime:%F %I:%M %p, day %j", want: "2021-05-03 05:05 PM, day 123"},
+	}
+	var p, _ = NewDateMathParser(WithTimeZone("+08:00"))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := p.Format(tim, tt.format); err != nil || got != tt.want {
+				t.Errorf("Format() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,218 @@
+package datemath_parser
+
+import (
+	"strings"
+	"time"
+)
+
+// locale holds names used by text fields of pattern, days start on monday.
+type locale struct {
+	months      []string
+	shortMonths []string
+	days        []string
+	shortDays   []string
+	halfDays    []string
+	// firstDay is the first day of week, which numbers localized day of week field.
+	firstDay time.Weekday
+}
+
+var englishLocale = &locale{
+	months: []string{"January", "February", "March", "April", "May", "June",
+		"July", "August", "September", "October", "November", "December"},
+	shortMonths: []string{"Jan", "Feb", "Mar", "Apr", "May", "Jun", "Jul", "Aug", "Sep", "Oct", "Nov", "Dec"},
+	days:        []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"},
+	shortDays:   []string{"Mon", "Tue", "Wed", "Thu", "Fri", "Sat", "Sun"},
+	halfDays:    []string{"AM", "PM"},
+	firstDay:    time.Sunday,
+}
+
+// locales is keyed by lower case language tag, names of month are in format context,
+// such as genitive case of slavic languages, which is what appears in dates.
+var locales = map[string]*locale{
+	"en": englishLocale,
+	"en-gb": {
+		months:      englishLocale.months,
+		shortMonths: englishLocale.shortMonths,
+		days:        englishLocale.days,
+		shortDays:   englishLocale.shortDays,
+		halfDays:    []string{"am", "pm"},
+		firstDay:    time.Monday,
+	},
+	"de": {
+		months: []string{"Januar", "Februar", "März", "April", "Mai", "Juni",
+			"Juli", "August", "September", "Oktober", "November", "Dezember"},
+		shortMonths: []string{"Jan.", "Feb.", "März", "Apr.", "Mai", "Juni", "Juli", "Aug.", "Sept.", "Okt.", "Nov.", "Dez."},
+		days:        []string{"Montag", "Dienstag", "Mittwoch", "Donnerstag", "Freitag", "Samstag", "Sonntag"},
+		shortDays:   []string{"Mo.", "Di.", "Mi.", "Do.", "Fr.", "Sa.", "So."},
+		halfDays:    []string{"AM", "PM"},
+		firstDay:    time.Monday,
+	},
+	"fr": {
+		months: []string{"janvier", "février", "mars", "avril", "mai", "juin",
+			"juillet", "août", "septembre", "octobre", "novembre", "décembre"},
+		shortMonths: []string{"janv.", "févr.", "mars", "avr.", "mai", "juin", "juil.", "août", "sept.", "oct.", "nov.", "déc."},
+		days:        []string{"lundi", "mardi", "mercredi", "jeudi", "vendredi", "samedi", "dimanche"},
+		shortDays:   []string{"lun.", "mar.", "mer.", "jeu.", "ven.", "sam.", "dim."},
+		halfDays:    []string{"AM", "PM"},
+		firstDay:    time.Monday,
+	},
+	"es": {
+		months: []string{"enero", "febrero", "marzo", "abril", "mayo", "junio",
+			"julio", "agosto", "septiembre", "octubre", "noviembre", "diciembre"},
+		shortMonths: []string{"ene", "feb", "mar", "abr", "may", "jun", "jul", "ago", "sept", "oct", "nov", "dic"},
+		days:        []string{"lunes", "martes", "miércoles", "jueves", "viernes", "sábado", "domingo"},
+		shortDays:   []string{"lun", "mar", "mié", "jue", "vie", "sáb", "dom"},
+		halfDays:    []string{"a. m.", "p. m."},
+		firstDay:    time.Monday,
+	},
+	"it": {
+		months: []string{"gennaio", "febbraio", "marzo", "aprile", "maggio", "giugno",
+			"luglio", "agosto", "settembre", "ottobre", "novembre", "dicembre"},
+		shortMonths: []string{"gen", "feb", "mar", "apr", "mag", "giu", "lug", "ago", "set", "ott", "nov", "dic"},
+		days:        []string{"lunedì", "martedì", "mercoledì", "giovedì", "venerdì", "sabato", "domenica"},
+		shortDays:   []string{"lun", "mar", "mer", "gio", "ven", "sab", "dom"},
+		halfDays:    []string{"AM", "PM"},
+		firstDay:    time.Monday,
+	},
+	"pt": {
+		months: []string{"janeiro", "fevereiro", "março", "abril", "maio", "junho",
+			"julho", "agosto", "setembro", "outubro", "novembro", "dezembro"},
+		shortMonths: []string{"jan.", "fev.", "mar.", "abr.", "mai.", "jun.", "jul.", "ago.", "set.", "out.", "nov.", "dez."},
+		days:        []string{"segunda-feira", "terça-feira", "quarta-feira", "quinta-feira", "sexta-feira", "sábado", "domingo"},
+		shortDays:   []string{"seg.", "ter.", "qua.", "qui.", "sex.", "sáb.", "dom."},
+		halfDays:    []string{"AM", "PM"},
+		firstDay:    time.Monday,
+	},
+	"nl": {
+		months: []string{"januari", "februari", "maart", "april", "mei", "juni",
+			"juli", "augustus", "september", "oktober", "november", "december"},
+		shortMonths: []string{"jan.", "feb.", "mrt.", "apr.", "mei", "jun.", "jul.", "aug.", "sep.", "okt.", "nov.", "dec."},
+		days:        []string{"maandag", "dinsdag", "woensdag", "donderdag", "vrijdag", "zaterdag", "zondag"},
+		shortDays:   []string{"ma", "di", "wo", "do", "vr", "za", "zo"},
+		halfDays:    []string{"a.m.", "p.m."},
+		firstDay:    time.Monday,
+	},
+	"sv": {
+		months: []string{"januari", "februari", "mars", "april", "maj", "juni",
+			"juli", "augusti", "september", "oktober", "november", "december"},
+		shortMonths: []string{"jan.", "feb.", "mars", "apr.", "maj", "juni", "juli", "aug.", "sep.", "okt.", "nov.", "dec."},
+		days:        []string{"måndag", "tisdag", "onsdag", "torsdag", "fredag", "lördag", "söndag"},
+		shortDays:   []string{"mån", "tis", "ons", "tors", "fre", "lör", "sön"},
+		halfDays:    []string{"fm", "em"},
+		firstDay:    time.Monday,
+	},
+	"da": {
+		months: []string{"januar", "februar", "marts", "april", "maj", "juni",
+			"juli", "august", "september", "oktober", "november", "december"},
+		shortMonths: []string{"jan.", "feb.", "mar.", "apr.", "maj", "jun.", "jul.", "aug.", "sep.", "okt.", "nov.", "dec."},
+		days:        []string{"mandag", "tirsdag", "onsdag", "torsdag", "fredag", "lørdag", "søndag"},
+		shortDays:   []string{"man.", "tirs.", "ons.", "tors.", "fre.", "lør.", "søn."},
+		halfDays:    []string{"AM", "PM"},
+		firstDay:    time.Monday,
+	},
+	"nb": {
+		months: []string{"januar", "februar", "mars", "april", "mai", "juni",
+			"juli", "august", "september", "oktober", "november", "desember"},
+		shortMonths: []string{"jan.", "feb.", "mar.", "apr.", "mai", "jun.", "jul.", "aug.", "sep.", "okt.", "nov.", "des."},
+		days:        []string{"mandag", "tirsdag", "onsdag", "torsdag", "fredag", "lørdag", "søndag"},
+		shortDays:   []string{"man.", "tir.", "ons.", "tor.", "fre.", "lør.", "søn."},
+		halfDays:    []string{"a.m.", "p.m."},
+		firstDay:    time.Monday,
+	},
+	"fi": {
+		months: []string{"tammikuuta", "helmikuuta", "maaliskuuta", "huhtikuuta", "toukokuuta", "kesäkuuta",
+			"heinäkuuta", "elokuuta", "syyskuuta", "lokakuuta", "marraskuuta", "joulukuuta"},
+		shortMonths: []string{"tammik.", "helmik.", "maalisk.", "huhtik.", "toukok.", "kesäk.",
+			"heinäk.", "elok.", "syysk.", "lokak.", "marrask.", "jouluk."},
+		days:      []string{"maanantai", "tiistai", "keskiviikko", "torstai", "perjantai", "lauantai", "sunnuntai"},
+		shortDays: []string{"ma", "ti", "ke", "to", "pe", "la", "su"},
+		halfDays:  []string{"ap.", "ip."},
+		firstDay:  time.Monday,
+	},
+	"pl": {
+		months: []string{"stycznia", "lutego", "marca", "kwietnia", "maja", "czerwca",
+			"lipca", "sierpnia", "września", "października", "listopada", "grudnia"},
+		shortMonths: []string{"sty", "lut", "mar", "kwi", "maj", "cze", "lip", "sie", "wrz", "paź", "lis", "gru"},
+		days:        []string{"poniedziałek", "wtorek", "środa", "czwartek", "piątek", "sobota", "niedziela"},
+		shortDays:   []string{"pon.", "wt.", "śr.", "czw.", "pt.", "sob.", "niedz."},
+		halfDays:    []string{"AM", "PM"},
+		firstDay:    time.Monday,
+	},
+	"cs": {
+		months: []string{"ledna", "února", "března", "dubna", "května", "června",
+			"července", "srpna", "září", "října", "listopadu", "prosince"},
+		shortMonths: []string{"led", "úno", "bře", "dub", "kvě", "čvn", "čvc", "srp", "zář", "říj", "lis", "pro"},
+		days:        []string{"pondělí", "úterý", "středa", "čtvrtek", "pátek", "sobota", "neděle"},
+		shortDays:   []string{"po", "út", "st", "čt", "pá", "so", "ne"},
+		halfDays:    []string{"dop.", "odp."},
+		firstDay:    time.Monday,
+	},
+	"ru": {
+		months: []string{"января", "февраля", "марта", "апреля", "мая", "июня",
+			"июля", "августа", "сентября", "октября", "ноября", "декабря"},
+		shortMonths: []string{"янв.", "февр.", "мар.", "апр.", "мая", "июн.", "июл.", "авг.", "сент.", "окт.", "нояб.", "дек."},
+		days:        []string{"понедельник", "вторник", "среда", "четверг", "пятница", "суббота", "воскресенье"},
+		shortDays:   []string{"пн", "вт", "ср", "чт", "пт", "сб", "вс"},
+		halfDays:    []string{"AM", "PM"},
+		firstDay:    time.Monday,
+	},
+	"tr": {
+		months: []string{"Ocak", "Şubat", "Mart", "Nisan", "Mayıs", "Haziran",
+			"Temmuz", "Ağustos", "Eylül", "Ekim", "Kasım", "Aralık"},
+		shortMonths: []string{"Oca", "Şub", "Mar", "Nis", "May", "Haz", "Tem", "Ağu", "Eyl", "Eki", "Kas", "Ara"},
+		days:        []string{"Pazartesi", "Salı", "Çarşamba", "Perşembe", "Cuma", "Cumartesi", "Pazar"},
+		shortDays:   []string{"Pzt", "Sal", "Çar", "Per", "Cum", "Cmt", "Paz"},
+		halfDays:    []string{"ÖÖ", "ÖS"},
+		firstDay:    time.Monday,
+	},
+	"ja": {
+		months:      []string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		shortMonths: []string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		days:        []string{"月曜日", "火曜日", "水曜日", "木曜日", "金曜日", "土曜日", "日曜日"},
+		shortDays:   []string{"月", "火", "水", "木", "金", "土", "日"},
+		halfDays:    []string{"午前", "午後"},
+		firstDay:    time.Sunday,
+	},
+	"zh": {
+		months:      []string{"一月", "二月", "三月", "四月", "五月", "六月", "七月", "八月", "九月", "十月", "十一月", "十二月"},
+		shortMonths: []string{"1月", "2月", "3月", "4月", "5月", "6月", "7月", "8月", "9月", "10月", "11月", "12月"},
+		days:        []string{"星期一", "星期二", "星期三", "星期四", "星期五", "星期六", "星期日"},
+		shortDays:   []string{"周一", "周二", "周三", "周四", "周五", "周六", "周日"},
+		halfDays:    []string{"上午", "下午"},
+		firstDay:    time.Monday,
+	},
+}
+
+// regionFirstDays overrides first day of week of language for some regions.
+var regionFirstDays = map[string]time.Weekday{
+	"en-au": time.Monday,
+	"en-ie": time.Monday,
+	"en-nz": time.Monday,
+	"pt-br": time.Sunday,
+	"zh-tw": time.Sunday,
+	"zh-hk": time.Sunday,
+}
+
+// lookupLocale finds locale by language tag like `de-DE` or `de_DE`, falls back to language.
+func lookupLocale(tag string) (*locale, bool) {
+	tag = strings.ToLower(strings.ReplaceAll(tag, "_", "-"))
+	var loc, ok = locales[tag]
+	if !ok {
+		var lang = tag
+		if i := strings.Index(tag, "-"); i != -1 {
+			lang = tag[:i]
+		}
+		if lang == "no" {
+			lang = "nb"
+		}
+		if loc, ok = locales[lang]; !ok {
+			return nil, false
+		}
+	}
+	if firstDay, ok := regionFirstDays[tag]; ok && firstDay != loc.firstDay {
+		var copied = *loc
+		copied.firstDay = firstDay
+		loc = &copied
+	}
+	return loc, true
+}
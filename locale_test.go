@@ -0,0 +1,152 @@
+package datemath_parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateMathParser_locale(t *testing.T) {
+	tests := []struct {
+		name   string
+		locale string
+		format string
+		expr   string
+		want   time.Time
+	}{
+		{
+			name:   "TestDateMathParser_locale01",
+			locale: "de-DE",
+			format: "dd MMM yyyy",
+			expr:   "10 Mai 2021",
+			want:   time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "TestDateMathParser_locale02",
+			locale: "de_DE",
+			format: "EEEE, d MMMM yyyy",
+			expr:   "Montag, 1 März 2021",
+			want:   time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "TestDateMathParser_locale03",
+			locale: "fr-FR",
+			format: "d MMM yyyy",
+			expr:   "3 févr 2021",
+			want:   time.Date(2021, 2, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "TestDateMathParser_locale04",
+			locale: "fr",
+			format: "EEEE d MMMM yyyy",
+			expr:   "mercredi 3 février 2021",
+			want:   time.Date(2021, 2, 3, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "TestDateMathParser_locale05",
+			locale: "es-ES",
+			format: "d 'de' MMMM 'de' yyyy",
+			expr:   "10 de mayo de 2021",
+			want:   time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "TestDateMathParser_locale06",
+			locale: "ru-RU",
+			format: "d MMMM yyyy",
+			expr:   "10 мая 2021",
+			want:   time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "TestDateMathParser_locale07",
+			locale: "pl",
+			format: "d MMMM yyyy",
+			expr:   "10 maja 2021",
+			want:   time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "TestDateMathParser_locale08",
+			locale: "ja-JP",
+			format: "yyyy'年'MMMd'日' a h'時'",
+			expr:   "2021年10月1日 午後 3時",
+			want:   time.Date(2021, 10, 1, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "TestDateMathParser_locale09",
+			locale: "nl-NL",
+			format: "d MMM yyyy",
+			expr:   "10 MRT 2021",
+			want:   time.Date(2021, 3, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "TestDateMathParser_locale10",
+			locale: "en-US",
+			format: "java:uuuu-MM-dd e",
+			expr:   "2021-05-09 1",
+			want:   time.Date(2021, 5, 9, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:   "TestDateMathParser_locale11",
+			locale: "de-DE",
+			format: "java:uuuu-MM-dd e",
+			expr:   "2021-05-09 7",
+			want:   time.Date(2021, 5, 9, 0, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p, err = NewDateMathParser(WithLocale(tt.locale), WithFormat([]string{tt.format}))
+			if err != nil {
+				t.Fatalf("NewDateMathParser() error = %v", err)
+			}
+			if got, err := p.Parse(tt.expr); err != nil {
+				t.Errorf("DateMathParser.Parse() error = %v", err)
+			} else if !got.Equal(tt.want) {
+				t.Errorf("DateMathParser.Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	t.Run("TestDateMathParser_localeUnknown", func(t *testing.T) {
+		if _, err := NewDateMathParser(WithLocale("xx-YY")); err == nil {
+			t.Errorf("expect error of unknown locale")
+		}
+	})
+}
+
+func TestDateMathParser_Format(t *testing.T) {
+	var tim = time.Date(2021, 5, 9, 14, 5, 7, 123456789, time.UTC)
+	tests := []struct {
+		name     string
+		locale   string
+		timeZone string
+		format   string
+		want     string
+	}{
+		{name: "TestDateMathParser_Format01", format: "yyyy-MM-dd'T'HH:mm:ss.SSSZZ", want: "2021-05-09T14:05:07.123Z"},
+		{name: "TestDateMathParser_Format02", timeZone: "+08:00", format: STRICT_DATE_OPTIONAL_TIME, want: "2021-05-09T22:05:07.123+08:00"},
+		{name: "TestDateMathParser_Format03", format: EPOCH_MILLIS, want: "1620569107123"},
+		{name: "TestDateMathParser_Format04", locale: "de-DE", format: "EEEE, d. MMMM yyyy", want: "Sonntag, 9. Mai 2021"},
+		{name: "TestDateMathParser_Format05", locale: "fr-FR", format: "EEE d MMM yy", want: "dim. 9 mai 21"},
+		{name: "TestDateMathParser_Format06", format: "hh:mm a", want: "02:05 PM"},
+		{name: "TestDateMathParser_Format07", locale: "en-US", format: "java:e", want: "1"},
+		{name: "TestDateMathParser_Format08", locale: "en-GB", format: "java:e", want: "7"},
+		{name: "TestDateMathParser_Format09", format: "java:uuuu-MM-dd'T'HH:mm:ss.SSSSSSSSSXXX", want: "2021-05-09T14:05:07.123456789Z"},
+		{name: "TestDateMathParser_Format10", timeZone: "Asia/Shanghai", format: "java:HH:mm VV", want: "22:05 Asia/Shanghai"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts = []DateMathParserOption{}
+			if tt.locale != "" {
+				opts = append(opts, WithLocale(tt.locale))
+			}
+			if tt.timeZone != "" {
+				opts = append(opts, WithTimeZone(tt.timeZone))
+			}
+			var p, _ = NewDateMathParser(opts...)
+			if got, err := p.Format(tim, tt.format); err != nil {
+				t.Errorf("DateMathParser.Format() error = %v", err)
+			} else if got != tt.want {
+				t.Errorf("DateMathParser.Format() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -12,15 +12,45 @@ type DateMathParserOption func(*DateMathParser) error
 
 func WithFormat(formats []string) DateMathParserOption {
 	return func(p *DateMathParser) error {
-		var parserFormats = []string{}
-		for _, format := range formats {
-			if jodaFormats, ok := BuiltInFormat[format]; ok {
-				parserFormats = append(parserFormats, jodaFormats...)
-			} else {
-				parserFormats = append(parserFormats, format)
-			}
+		p.requestedFormats = append([]string{}, formats...)
+		return nil
+	}
+}
+
+func WithRegistry(registry *FormatRegistry) DateMathParserOption {
+	return func(p *DateMathParser) error {
+		p.registry = registry
+		return nil
+	}
+}
+
+func WithPatternDialect(dialect PatternDialect) DateMathParserOption {
+	return func(p *DateMathParser) error {
+		p.PatternDialect = dialect
+		return nil
+	}
+}
+
+func WithLenientFraction(lenient bool) DateMathParserOption {
+	return func(p *DateMathParser) error {
+		p.LenientFraction = lenient
+		return nil
+	}
+}
+
+func WithLocale(tag string) DateMathParserOption {
+	return func(p *DateMathParser) error {
+		if _, ok := lookupLocale(tag); !ok {
+			return fmt.Errorf("locale: %s is not supported", tag)
 		}
-		p.Formats = parserFormats
+		p.Locale = tag
+		return nil
+	}
+}
+
+func WithRoundUp(roundUp bool) DateMathParserOption {
+	return func(p *DateMathParser) error {
+		p.RoundUp = roundUp
 		return nil
 	}
 }
@@ -63,3 +93,128 @@ func WithTimeZone(timeZone string) DateMathParserOption {
 		}
 	}
 }
+
+func WithYearInference(strategy YearInference) DateMathParserOption {
+	return func(p *DateMathParser) error {
+		if strategy < YearInferenceClosest || strategy > YearInferenceNone {
+			return fmt.Errorf("year inference: %d is invalid", strategy)
+		}
+		p.YearInference = strategy
+		return nil
+	}
+}
+
+func WithNow(now func() time.Time) DateMathParserOption {
+	return func(p *DateMathParser) error {
+		p.Now = now
+		return nil
+	}
+}
+
+func WithTwoDigitYearPivot(year int) DateMathParserOption {
+	return func(p *DateMathParser) error {
+		if year <= 0 {
+			return fmt.Errorf("two digit year pivot: %d should be positive", year)
+		}
+		p.TwoDigitYearPivot = year
+		p.twoDigitYearSliding = false
+		return nil
+	}
+}
+
+// WithTwoDigitYearWindow makes two digit year map into 100 years starting yearsBefore years
+// before now, e.g. 80 maps into 80 years before and 19 years after the year of now.
+func WithTwoDigitYearWindow(yearsBefore int) DateMathParserOption {
+	return func(p *DateMathParser) error {
+		if yearsBefore < 0 || yearsBefore > 99 {
+			return fmt.Errorf("two digit year window: %d is out of range [0, 99]", yearsBefore)
+		}
+		p.twoDigitYearWindow = yearsBefore
+		p.twoDigitYearSliding = true
+		return nil
+	}
+}
+
+func WithStrict(strict bool) DateMathParserOption {
+	return func(p *DateMathParser) error {
+		p.Strict = strict
+		return nil
+	}
+}
+
+func WithDayFirst(dayFirst bool) DateMathParserOption {
+	return func(p *DateMathParser) error {
+		p.DayFirst = dayFirst
+		return nil
+	}
+}
+
+func WithMaxOffset(maxOffset time.Duration) DateMathParserOption {
+	return func(p *DateMathParser) error {
+		if maxOffset < 0 {
+			return fmt.Errorf("max offset: %s should not be negative", maxOffset)
+		}
+		p.MaxOffset = maxOffset
+		return nil
+	}
+}
+
+func WithDialect(dialect Dialect) DateMathParserOption {
+	return func(p *DateMathParser) error {
+		if dialect < DialectElasticSearch || dialect > DialectSplunk {
+			return fmt.Errorf("dialect: %d is unknown", dialect)
+		}
+		p.Dialect = dialect
+		return nil
+	}
+}
+
+func WithRoundOffset(offset time.Duration) DateMathParserOption {
+	return func(p *DateMathParser) error {
+		p.RoundOffset = offset
+		return nil
+	}
+}
+
+func WithFiscalYearStartMonth(month time.Month) DateMathParserOption {
+	return func(p *DateMathParser) error {
+		if month < time.January || month > time.December {
+			return fmt.Errorf("fiscal year start month: %d is out of range [1, 12]", month)
+		}
+		p.FiscalYearStartMonth = month
+		return nil
+	}
+}
+
+// WithWeekStart sets the first day of week, which drives rounding `/w`, localized day of week
+// and weeks of java syntax, weeks start on monday like ElasticSearch by default.
+func WithWeekStart(day time.Weekday) DateMathParserOption {
+	return func(p *DateMathParser) error {
+		if day < time.Sunday || day > time.Saturday {
+			return fmt.Errorf("week start: %d is invalid", day)
+		}
+		p.weekStart = &day
+		p.weekStartFromLocale = false
+		return nil
+	}
+}
+
+// WithLocaleWeekStart makes the first day of week derived from locale of parser, like sunday of `en-US`,
+// it overrides WithWeekStart given before and takes english locale when no locale is set.
+func WithLocaleWeekStart() DateMathParserOption {
+	return func(p *DateMathParser) error {
+		p.weekStart = nil
+		p.weekStartFromLocale = true
+		return nil
+	}
+}
+
+func WithCalendar(calendar HolidayCalendar) DateMathParserOption {
+	return func(p *DateMathParser) error {
+		if calendar == nil {
+			return fmt.Errorf("calendar should not be nil")
+		}
+		p.Calendar = calendar
+		return nil
+	}
+}
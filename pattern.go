@@ -0,0 +1,1026 @@
+package datemath_parser
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// PatternDialect is the syntax of format pattern.
+type PatternDialect int
+
+const (
+	// PatternJoda is joda time syntax, which is used by ElasticSearch before 7.0 and BuiltInFormat.
+	PatternJoda PatternDialect = iota
+	// PatternJava is java.time DateTimeFormatter syntax, which is used by ElasticSearch 7.0+.
+	PatternJava
+	// PatternGo is go time layout like `2006-01-02 15:04:05`, or name of layout constant like `RFC3339`.
+	PatternGo
+	// PatternStrftime is C strftime syntax like `%Y-%m-%d %H:%M:%S`, which is used by python and C.
+	PatternStrftime
+)
+
+// patternPrefixes selects dialect of a single pattern, such as `java:uuuu-MM-dd'T'HH:mm:ssXXX`.
+var patternPrefixes = map[string]PatternDialect{
+	"joda:":     PatternJoda,
+	"java:":     PatternJava,
+	"go:":       PatternGo,
+	"strftime:": PatternStrftime,
+}
+
+type field int
+
+const (
+	fieldLiteral field = iota
+	fieldYear
+	fieldYearOfEra
+	fieldWeekYear
+	fieldMonth
+	fieldDay
+	fieldDayOfYear
+	fieldWeek
+	fieldDayOfWeek
+	fieldHalfDay
+	fieldHourOfDay          // 0~23
+	fieldClockHourOfDay     // 1~24
+	fieldHourOfHalfDay      // 0~11
+	fieldClockHourOfHalfDay // 1~12
+	fieldMinute
+	fieldSecond
+	fieldFraction
+	fieldNano
+	fieldOffset
+	fieldZoneID
+	fieldZoneName // abbreviation like `GMT` or `PST`
+	fieldOptional
+)
+
+// fieldPrecisions is precision of a parsed field, fraction depends on count of digits.
+var fieldPrecisions = map[field]Precision{
+	fieldYear:               PrecisionYear,
+	fieldYearOfEra:          PrecisionYear,
+	fieldWeekYear:           PrecisionYear,
+	fieldMonth:              PrecisionMonth,
+	fieldWeek:               PrecisionWeek,
+	fieldDay:                PrecisionDay,
+	fieldDayOfYear:          PrecisionDay,
+	fieldDayOfWeek:          PrecisionDay,
+	fieldHourOfDay:          PrecisionHour,
+	fieldClockHourOfDay:     PrecisionHour,
+	fieldHourOfHalfDay:      PrecisionHour,
+	fieldClockHourOfHalfDay: PrecisionHour,
+	fieldMinute:             PrecisionMinute,
+	fieldSecond:             PrecisionSecond,
+	fieldNano:               PrecisionNanosecond,
+}
+
+// fieldMaxDigits is max count of digits of numeric field.
+var fieldMaxDigits = map[field]int{
+	fieldYear:               9,
+	fieldYearOfEra:          9,
+	fieldWeekYear:           9,
+	fieldMonth:              2,
+	fieldDay:                2,
+	fieldDayOfYear:          3,
+	fieldWeek:               2,
+	fieldDayOfWeek:          1,
+	fieldHourOfDay:          2,
+	fieldClockHourOfDay:     2,
+	fieldHourOfHalfDay:      2,
+	fieldClockHourOfHalfDay: 2,
+	fieldMinute:             2,
+	fieldSecond:             2,
+	fieldFraction:           9,
+	fieldNano:               9,
+}
+
+var jodaLetters = map[rune]field{
+	'y': fieldYear,
+	'Y': fieldYearOfEra,
+	'x': fieldWeekYear,
+	'w': fieldWeek,
+	'e': fieldDayOfWeek,
+	'E': fieldDayOfWeek,
+	'D': fieldDayOfYear,
+	'M': fieldMonth,
+	'd': fieldDay,
+	'a': fieldHalfDay,
+	'K': fieldHourOfHalfDay,
+	'h': fieldClockHourOfHalfDay,
+	'H': fieldHourOfDay,
+	'k': fieldClockHourOfDay,
+	'm': fieldMinute,
+	's': fieldSecond,
+	'S': fieldFraction,
+	'z': fieldZoneName,
+	'Z': fieldOffset,
+}
+
+// jodaUnsupported are letters of joda syntax without field here, era and century of era.
+const jodaUnsupported = "GC"
+
+var javaLetters = map[rune]field{
+	'u': fieldYear,
+	'y': fieldYearOfEra,
+	'Y': fieldWeekYear,
+	'w': fieldWeek,
+	'e': fieldDayOfWeek,
+	'c': fieldDayOfWeek,
+	'E': fieldDayOfWeek,
+	'D': fieldDayOfYear,
+	'M': fieldMonth,
+	'L': fieldMonth,
+	'd': fieldDay,
+	'a': fieldHalfDay,
+	'K': fieldHourOfHalfDay,
+	'h': fieldClockHourOfHalfDay,
+	'H': fieldHourOfDay,
+	'k': fieldClockHourOfDay,
+	'm': fieldMinute,
+	's': fieldSecond,
+	'S': fieldFraction,
+	'n': fieldNano,
+	'X': fieldOffset,
+	'x': fieldOffset,
+	'Z': fieldOffset,
+	'V': fieldZoneID,
+	'z': fieldZoneName,
+}
+
+// offsetStyle describes accepted forms of zone offset.
+type offsetStyle struct {
+	zulu    bool // `Z` is accepted for zero offset
+	colon   bool // hour and minute are separated by `:`
+	minutes bool // minutes are required
+	// lenient accepts `Z`, `+HH`, `+HHmm` and `+HH:mm` followed by optional region id
+	// in brackets like `+02:00[Europe/Paris]`, colon and minutes only affect formatting.
+	lenient bool
+}
+
+type element struct {
+	field   field
+	count   int // count of pattern letters
+	literal string
+	text    bool // value is name like `Jan` or `Monday` instead of number
+	// localized day of week is numbered from the first day of week of locale instead of monday
+	localized bool
+	fixed     bool // numeric value adjacent to another numeric value takes exactly count digits
+	space     bool // numeric value is padded with spaces instead of zeros, like `%e` of strftime
+	offset    offsetStyle
+	// optional holds elements of optional section `[...]`, which is skipped when it can't be parsed.
+	optional []element
+}
+
+func (e element) numeric() bool {
+	switch e.field {
+	case fieldLiteral, fieldHalfDay, fieldOffset, fieldZoneID, fieldZoneName, fieldOptional:
+		return false
+	}
+	return !e.text
+}
+
+// pattern is a compiled format pattern.
+type pattern struct {
+	source   string
+	dialect  PatternDialect
+	elements []element
+	options  patternOptions
+}
+
+// patternOptions adjust patterns of built-in formats, which can't be told by pattern syntax.
+type patternOptions struct {
+	// fixedWidth makes numeric fields of 2 or more letters take exactly count of digits,
+	// like `strict_` formats of ElasticSearch, year takes count or more digits.
+	fixedWidth bool
+	// lenientFraction makes fraction of second take 1~9 digits like LenientFraction of parser.
+	lenientFraction bool
+	// locale gives names of month and day of week instead of locale of parser, like english of internet standards.
+	locale *locale
+}
+
+// context returns ctx adjusted by options of pattern.
+func (p *pattern) context(ctx *parseContext) *parseContext {
+	if p.options.locale == nil {
+		return ctx
+	}
+	var c = *ctx
+	c.locale = p.options.locale
+	return &c
+}
+
+// splitPatternDialect strips dialect prefix of pattern, dialect is def if there is no prefix.
+func splitPatternDialect(source string, def PatternDialect) (string, PatternDialect) {
+	for prefix, dialect := range patternPrefixes {
+		if strings.HasPrefix(source, prefix) {
+			return source[len(prefix):], dialect
+		}
+	}
+	return source, def
+}
+
+func compilePattern(source string, dialect PatternDialect) (*pattern, error) {
+	switch dialect {
+	case PatternGo:
+		return &pattern{source: source, dialect: dialect}, nil
+	case PatternStrftime:
+		var elements, err = compileStrftime(source)
+		if err != nil {
+			return nil, err
+		}
+		return &pattern{source: source, dialect: dialect, elements: elements}, nil
+	}
+	var c = &patternCompiler{source: source, runes: []rune(source), dialect: dialect, letters: jodaLetters}
+	if dialect == PatternJava {
+		c.letters = javaLetters
+	}
+	var elements, err = c.compile(0)
+	if err != nil {
+		return nil, err
+	}
+	return &pattern{source: source, dialect: dialect, elements: elements}, nil
+}
+
+type patternCompiler struct {
+	source  string
+	runes   []rune
+	pos     int
+	dialect PatternDialect
+	letters map[rune]field
+}
+
+// compile compiles elements until the end of pattern or the `]` closing optional section at depth.
+func (c *patternCompiler) compile(depth int) ([]element, error) {
+	var elements = []element{}
+	for ; c.pos < len(c.runes); c.pos++ {
+		var r = c.runes[c.pos]
+		switch {
+		case r == '\'':
+			var text, err = c.quoted()
+			if err != nil {
+				return nil, err
+			}
+			elements = appendLiteral(elements, text)
+		case r == '[':
+			c.pos++
+			var optional, err = c.compile(depth + 1)
+			if err != nil {
+				return nil, err
+			}
+			elements = append(elements, element{field: fieldOptional, optional: optional})
+		case r == ']':
+			if depth == 0 {
+				return nil, fmt.Errorf("pattern: %s is invalid, ] is not paired with [", c.source)
+			}
+			markFixed(elements)
+			return elements, nil
+		case !isLetter(r):
+			if c.dialect == PatternJava && strings.ContainsRune("{}#", r) {
+				return nil, fmt.Errorf("pattern: %s is invalid, %c is not supported", c.source, r)
+			}
+			elements = appendLiteral(elements, string(r))
+		default:
+			var j = c.pos
+			for ; j < len(c.runes) && c.runes[j] == r; j++ {
+			}
+			var count = j - c.pos
+			c.pos = j - 1
+			var f, ok = c.letters[r]
+			if !ok {
+				if c.dialect == PatternJoda && !strings.ContainsRune(jodaUnsupported, r) {
+					// letters out of joda syntax are literal, such as bare T in `yyyy-MM-ddTHH`
+					elements = appendLiteral(elements, strings.Repeat(string(r), count))
+					continue
+				}
+				return nil, fmt.Errorf("pattern: %s is invalid, letter %c is not supported", c.source, r)
+			}
+			var e, err = newElement(f, r, count, c.dialect)
+			if err != nil {
+				return nil, fmt.Errorf("pattern: %s is invalid, %s", c.source, err)
+			}
+			elements = append(elements, e)
+		}
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("pattern: %s is invalid, [ is not closed", c.source)
+	}
+	markFixed(elements)
+	return elements, nil
+}
+
+// quoted returns text quoted from current position, two adjacent quotes are a real quote.
+func (c *patternCompiler) quoted() (string, error) {
+	var j = c.pos + 1
+	var text = []rune{}
+	for ; j < len(c.runes); j++ {
+		if c.runes[j] == '\'' {
+			if j+1 < len(c.runes) && c.runes[j+1] == '\'' {
+				text = append(text, '\'')
+				j++
+				continue
+			}
+			break
+		}
+		text = append(text, c.runes[j])
+	}
+	if j == len(c.runes) {
+		return "", fmt.Errorf("pattern: %s is invalid, quote is not closed", c.source)
+	}
+	if j == c.pos+1 {
+		text = append(text, '\'')
+	}
+	c.pos = j
+	return string(text), nil
+}
+
+// markFixed marks numeric elements which are adjacent to each other.
+func markFixed(elements []element) {
+	for i := 0; i+1 < len(elements); i++ {
+		if elements[i].numeric() && elements[i+1].numeric() {
+			elements[i].fixed = true
+			elements[i+1].fixed = true
+		}
+	}
+}
+
+func appendLiteral(elements []element, s string) []element {
+	if n := len(elements); n != 0 && elements[n-1].field == fieldLiteral {
+		elements[n-1].literal += s
+		return elements
+	}
+	return append(elements, element{field: fieldLiteral, literal: s})
+}
+
+func newElement(f field, r rune, count int, dialect PatternDialect) (element, error) {
+	var e = element{field: f, count: count}
+	switch f {
+	case fieldMonth:
+		e.text = count >= 3
+	case fieldDayOfWeek:
+		e.text = r == 'E' || count >= 3
+		e.localized = dialect == PatternJava && !e.text
+	case fieldOffset:
+		switch {
+		case dialect == PatternJoda && count == 1:
+			e.offset = offsetStyle{zulu: true, minutes: true, lenient: true}
+		case dialect == PatternJoda && count == 2:
+			e.offset = offsetStyle{zulu: true, colon: true, minutes: true, lenient: true}
+		case dialect == PatternJoda:
+			e.field = fieldZoneID
+		case r == 'Z' && count <= 3:
+			e.offset = offsetStyle{minutes: true}
+		case r == 'Z' && count == 5:
+			e.offset = offsetStyle{zulu: true, colon: true, minutes: true}
+		case r == 'Z':
+			return e, fmt.Errorf("localized offset ZZZZ is not supported")
+		case count == 1:
+			e.offset = offsetStyle{zulu: r == 'X'}
+		case count == 2 || count == 4:
+			e.offset = offsetStyle{zulu: r == 'X', minutes: true}
+		default:
+			e.offset = offsetStyle{zulu: r == 'X', colon: true, minutes: true}
+		}
+	case fieldZoneID:
+		if count != 2 {
+			return e, fmt.Errorf("zone id must be VV")
+		}
+	}
+	return e, nil
+}
+
+func isLetter(r rune) bool {
+	return (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z')
+}
+
+// parsed holds fields parsed from input.
+type parsed struct {
+	values map[field]int
+	digits int            // count of fraction digits
+	loc    *time.Location // zone given by offset or zone id
+	region *time.Location // region id in brackets after offset, which only changes location of result
+	weeks  weekFields     // weeks of week based year and week fields
+}
+
+func (r *parsed) clone() *parsed {
+	var res = *r
+	res.values = make(map[field]int, len(r.values))
+	for f, v := range r.values {
+		res.values[f] = v
+	}
+	return &res
+}
+
+// parseContext holds settings of parser used while parsing with pattern.
+type parseContext struct {
+	loc             *time.Location // used when value has no zone
+	lenientFraction bool           // fraction of second takes 1~9 digits whatever count of S is
+	locale          *locale
+	now             time.Time // used to infer year of value without year, zero means current time
+	yearInference   YearInference
+	// twoDigitYearPivot is the first year of 100 years which two digit year maps into, 0 is default of dialect
+	twoDigitYearPivot int
+	// weekStart is the first day of week given to parser, which numbers localized day of week and
+	// defines weeks of java syntax, nil means the first day of locale and ISO weeks.
+	weekStart *time.Weekday
+}
+
+// firstDay returns the first day of week, which numbers localized day of week.
+func (ctx *parseContext) firstDay() time.Weekday {
+	if ctx.weekStart != nil {
+		return *ctx.weekStart
+	}
+	return ctx.locale.firstDay
+}
+
+// weekFields returns weeks of week fields, which are ISO weeks except java syntax with week start of parser.
+func (p *pattern) weekFields(ctx *parseContext) weekFields {
+	if p.dialect == PatternJava && ctx.weekStart != nil {
+		return weekFieldsOf(*ctx.weekStart)
+	}
+	return isoWeekFields
+}
+
+// parse parses value into time.
+func (p *pattern) parse(value string, ctx *parseContext) (time.Time, Precision, error) {
+	if p.dialect == PatternGo {
+		return p.parseLayout(value, ctx)
+	}
+	ctx = p.context(ctx)
+	var res = &parsed{values: map[field]int{}, weeks: p.weekFields(ctx)}
+	var pos, err = p.parseElements(p.elements, value, res, ctx)
+	if err != nil {
+		return emptyTime, PrecisionUnknown, fmt.Errorf("failed to parse %s with pattern: %s, %s", value, p.source, err)
+	}
+	if pos != len(value) {
+		return emptyTime, PrecisionUnknown, fmt.Errorf("failed to parse %s with pattern: %s, unexpected text: %s", value, p.source, value[pos:])
+	}
+	var loc = ctx.loc
+	if res.loc != nil {
+		loc = res.loc
+	}
+	var tim time.Time
+	if res.needsYear() && ctx.yearInference != YearInferenceNone {
+		var now = ctx.now
+		if now.IsZero() {
+			now = time.Now()
+		}
+		tim, err = res.inferYear(loc, now, ctx.yearInference)
+	} else {
+		tim, err = res.resolve(loc)
+	}
+	if err != nil {
+		return emptyTime, PrecisionUnknown, fmt.Errorf("failed to parse %s with pattern: %s, %s", value, p.source, err)
+	}
+	if res.region != nil {
+		tim = tim.In(res.region)
+	}
+	return tim, res.precision(), nil
+}
+
+// parseElements parses prefix of value with elements, and returns count of consumed bytes.
+func (p *pattern) parseElements(elements []element, value string, res *parsed, ctx *parseContext) (int, error) {
+	var pos = 0
+	for _, e := range elements {
+		if e.field == fieldOptional {
+			var saved = res.clone()
+			if n, err := p.parseElements(e.optional, value[pos:], res, ctx); err != nil {
+				*res = *saved
+			} else {
+				pos += n
+			}
+			continue
+		}
+		var n, err = p.parseElement(e, value[pos:], res, ctx)
+		if err != nil {
+			return pos, err
+		}
+		pos += n
+	}
+	return pos, nil
+}
+
+// parseElement parses prefix of value with e, and returns count of consumed bytes.
+func (p *pattern) parseElement(e element, value string, res *parsed, ctx *parseContext) (int, error) {
+	switch {
+	case e.field == fieldLiteral:
+		if !strings.HasPrefix(value, e.literal) {
+			return 0, fmt.Errorf("expect %q", e.literal)
+		}
+		return len(e.literal), nil
+	case e.field == fieldHalfDay:
+		return parseName(value, 0, fieldHalfDay, res, ctx.locale.halfDays)
+	case e.field == fieldMonth && e.text:
+		return parseName(value, 1, fieldMonth, res, ctx.locale.months, ctx.locale.shortMonths)
+	case e.field == fieldDayOfWeek && e.text:
+		return parseName(value, 1, fieldDayOfWeek, res, ctx.locale.days, ctx.locale.shortDays)
+	case e.field == fieldOffset:
+		return parseOffset(value, e.offset, res)
+	case e.field == fieldZoneID:
+		return parseZoneID(value, res)
+	case e.field == fieldZoneName:
+		return parseZoneName(value, res)
+	}
+	var padding = 0
+	if e.space {
+		for padding < len(value) && padding < e.count-1 && value[padding] == ' ' {
+			padding++
+		}
+		value = value[padding:]
+	}
+	// signed year like `+10000` or `-0044`, which isn't adjacent to other numeric values
+	var signed = (e.field == fieldYear || e.field == fieldWeekYear) && e.count != 2 && !e.fixed
+	var sign = 1
+	if signed && len(value) != 0 && (value[0] == '+' || value[0] == '-') {
+		if value[0] == '-' {
+			sign = -1
+		}
+		value = value[1:]
+		padding++
+	}
+	var minDigits, maxDigits = 1, fieldMaxDigits[e.field]
+	var fixed = e.fixed || ((p.dialect == PatternJava || p.options.fixedWidth) && e.count >= 2) ||
+		(e.count == 2 && (e.field == fieldYear || e.field == fieldYearOfEra || e.field == fieldWeekYear))
+	if e.field == fieldFraction {
+		fixed = !ctx.lenientFraction && !p.options.lenientFraction && (e.fixed || p.dialect == PatternJava)
+	}
+	if signed && fixed {
+		// java year takes at least count of digits, and more digits for extended year
+		minDigits, maxDigits, fixed = e.count, fieldMaxDigits[e.field], false
+	}
+	if fixed {
+		minDigits, maxDigits = e.count, e.count
+	}
+	var n = 0
+	for n < len(value) && n < maxDigits && value[n] >= '0' && value[n] <= '9' {
+		n++
+	}
+	if n < minDigits {
+		return 0, fmt.Errorf("expect %d digits", minDigits)
+	}
+	var v = 0
+	for _, c := range value[:n] {
+		v = v*10 + int(c-'0')
+	}
+	if e.field == fieldFraction {
+		res.digits = n
+		for i := n; i < 9; i++ {
+			v *= 10
+		}
+	} else if e.count == 2 && (e.field == fieldYear || e.field == fieldYearOfEra || e.field == fieldWeekYear) {
+		v = twoDigitYear(v, p.dialect, ctx.twoDigitYearPivot)
+	} else if e.localized {
+		// convert to iso day of week, which starts on monday
+		v = (v+int(ctx.firstDay())+5)%7 + 1
+	}
+	res.values[e.field] = sign * v
+	return padding + n, nil
+}
+
+// parseName parses one of names ignoring case, and stores base plus its index,
+// longer name is preferred and trailing period of abbreviation is optional.
+func parseName(value string, base int, f field, res *parsed, names ...[]string) (int, error) {
+	var best, index = 0, -1
+	for _, list := range names {
+		for i, name := range list {
+			var candidates = []string{name}
+			if strings.HasSuffix(name, ".") {
+				candidates = append(candidates, strings.TrimSuffix(name, "."))
+			}
+			for _, s := range candidates {
+				if len(s) > best && len(value) >= len(s) && strings.EqualFold(value[:len(s)], s) {
+					best, index = len(s), i
+				}
+			}
+		}
+	}
+	if index == -1 {
+		return 0, fmt.Errorf("expect one of %v", names[0])
+	}
+	res.values[f] = base + index
+	return best, nil
+}
+
+func parseOffset(value string, style offsetStyle, res *parsed) (int, error) {
+	var n, err = parseOffsetValue(value, style, res)
+	if err != nil || !style.lenient || !strings.HasPrefix(value[n:], "[") {
+		return n, err
+	}
+	var end = strings.Index(value[n:], "]")
+	if end == -1 {
+		return 0, fmt.Errorf("expect ] after region id")
+	}
+	var region, loadErr = time.LoadLocation(value[n+1 : n+end])
+	if loadErr != nil {
+		return 0, fmt.Errorf("unknown region id: %s", value[n+1:n+end])
+	}
+	res.region = region
+	return n + end + 1, nil
+}
+
+func parseOffsetValue(value string, style offsetStyle, res *parsed) (int, error) {
+	if style.zulu && strings.HasPrefix(value, "Z") {
+		res.loc = time.UTC
+		res.values[fieldOffset] = 0
+		return 1, nil
+	}
+	if len(value) < 3 || (value[0] != '+' && value[0] != '-') || !isDigits(value[1:3]) {
+		return 0, fmt.Errorf("expect zone offset")
+	}
+	var hour = int(value[1]-'0')*10 + int(value[2]-'0')
+	var minute, n = 0, 3
+	var rest = value[3:]
+	if (style.colon || style.lenient) && strings.HasPrefix(rest, ":") {
+		rest = rest[1:]
+		if len(rest) < 2 || !isDigits(rest[:2]) {
+			return 0, fmt.Errorf("expect minutes of zone offset")
+		}
+		minute, n = int(rest[0]-'0')*10+int(rest[1]-'0'), 6
+	} else if (!style.colon || style.lenient) && len(rest) >= 2 && isDigits(rest[:2]) {
+		minute, n = int(rest[0]-'0')*10+int(rest[1]-'0'), 5
+	} else if style.minutes && !style.lenient {
+		return 0, fmt.Errorf("expect minutes of zone offset")
+	}
+	if hour > 18 || minute > 59 {
+		return 0, fmt.Errorf("zone offset %s is out of range", value[:n])
+	}
+	var offset = (hour*60 + minute) * 60
+	if value[0] == '-' {
+		offset = -offset
+	}
+	res.values[fieldOffset] = offset
+	res.loc = time.FixedZone("", offset)
+	return n, nil
+}
+
+func parseZoneID(value string, res *parsed) (int, error) {
+	var n = 0
+	for n < len(value) && (isLetter(rune(value[n])) || strings.ContainsRune("0123456789/_+-", rune(value[n]))) {
+		n++
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("expect zone id")
+	}
+	var loc, err = time.LoadLocation(value[:n])
+	if err != nil {
+		return 0, fmt.Errorf("unknown zone id: %s", value[:n])
+	}
+	res.loc = loc
+	res.values[fieldZoneID] = 0
+	return n, nil
+}
+
+// zoneNames are zone abbreviations of RFC 822 and RFC 2822, which are used by mail and http headers,
+// and common european ones printed by strftime `%Z`.
+var zoneNames = map[string]int{
+	"GMT":  0,
+	"UTC":  0,
+	"UT":   0,
+	"EST":  -5 * 3600,
+	"EDT":  -4 * 3600,
+	"CST":  -6 * 3600,
+	"CDT":  -5 * 3600,
+	"MST":  -7 * 3600,
+	"MDT":  -6 * 3600,
+	"PST":  -8 * 3600,
+	"PDT":  -7 * 3600,
+	"WET":  0,
+	"WEST": 1 * 3600,
+	"CET":  1 * 3600,
+	"CEST": 2 * 3600,
+	"EET":  2 * 3600,
+	"EEST": 3 * 3600,
+}
+
+// parseZoneName parses zone abbreviation, or zone offset like `+0800` instead.
+func parseZoneName(value string, res *parsed) (int, error) {
+	var best = ""
+	for name := range zoneNames {
+		if len(name) > len(best) && strings.HasPrefix(value, name) {
+			best = name
+		}
+	}
+	if best == "" {
+		if n, err := parseOffsetValue(value, offsetStyle{minutes: true}, res); err == nil {
+			return n, nil
+		}
+		return 0, fmt.Errorf("expect zone name")
+	}
+	if offset := zoneNames[best]; offset == 0 {
+		res.loc = time.UTC
+	} else {
+		res.loc = time.FixedZone(best, offset)
+	}
+	res.values[fieldZoneName] = 0
+	return len(best), nil
+}
+
+// formatZoneName prints `GMT` for zero offset and known abbreviation, otherwise the offset.
+func formatZoneName(tim time.Time) string {
+	var name, offset = tim.Zone()
+	if offset == 0 {
+		return "GMT"
+	}
+	if known, ok := zoneNames[name]; ok && known == offset {
+		return name
+	}
+	return formatOffset(tim, offsetStyle{minutes: true})
+}
+
+// resolve builds time from parsed fields, missing date fields default to 0000-01-01.
+func (r *parsed) resolve(loc *time.Location) (time.Time, error) {
+	var year, month, day, err = r.date()
+	if err != nil {
+		return emptyTime, err
+	}
+	hour, err := r.hour()
+	if err != nil {
+		return emptyTime, err
+	}
+	var minute, second = r.value(fieldMinute, 0), r.value(fieldSecond, 0)
+	if minute > 59 {
+		return emptyTime, fmt.Errorf("minute %d is out of range [0, 59]", minute)
+	}
+	if second > 59 {
+		return emptyTime, fmt.Errorf("second %d is out of range [0, 59]", second)
+	}
+	var nano = r.value(fieldFraction, r.value(fieldNano, 0))
+	var tim = time.Date(year, month, day, hour, minute, second, nano, loc)
+	if dow, ok := r.values[fieldDayOfWeek]; ok && isoWeekday(tim) != dow {
+		return emptyTime, fmt.Errorf("day of week %d conflicts with date", dow)
+	}
+	return tim, nil
+}
+
+// date resolves year, month and day from week date, ordinal date or calendar date fields.
+func (r *parsed) date() (int, time.Month, int, error) {
+	var year = r.value(fieldYear, r.value(fieldYearOfEra, 0))
+	var _, hasWeekYear = r.values[fieldWeekYear]
+	var _, hasWeek = r.values[fieldWeek]
+	if hasWeekYear || hasWeek {
+		if _, ok := r.values[fieldMonth]; ok {
+			return 0, 0, 0, fmt.Errorf("week date conflicts with month")
+		}
+		if _, ok := r.values[fieldDay]; ok {
+			return 0, 0, 0, fmt.Errorf("week date conflicts with day of month")
+		}
+		var tim, err = r.weeks.date(r.value(fieldWeekYear, year), r.value(fieldWeek, 1), r.value(fieldDayOfWeek, 0))
+		if err != nil {
+			return 0, 0, 0, err
+		}
+		return tim.Year(), tim.Month(), tim.Day(), nil
+	}
+	if dayOfYear, ok := r.values[fieldDayOfYear]; ok {
+		if _, ok := r.values[fieldMonth]; ok {
+			return 0, 0, 0, fmt.Errorf("day of year conflicts with month")
+		}
+		if _, ok := r.values[fieldDay]; ok {
+			return 0, 0, 0, fmt.Errorf("day of year conflicts with day of month")
+		}
+		if days := daysInYear(year); dayOfYear < 1 || dayOfYear > days {
+			return 0, 0, 0, fmt.Errorf("day of year %d is out of range [1, %d] of year %d", dayOfYear, days, year)
+		}
+		var tim = time.Date(year, time.January, dayOfYear, 0, 0, 0, 0, time.UTC)
+		return tim.Year(), tim.Month(), tim.Day(), nil
+	}
+	var month = r.value(fieldMonth, 1)
+	var day = r.value(fieldDay, 1)
+	if month < 1 || month > 12 {
+		return 0, 0, 0, fmt.Errorf("month %d is out of range [1, 12]", month)
+	}
+	if last := daysIn(time.Month(month), year); day < 1 || day > last {
+		return 0, 0, 0, fmt.Errorf("day %d is out of range [1, %d]", day, last)
+	}
+	return year, time.Month(month), day, nil
+}
+
+// isoWeekday returns day of week from monday (1) to sunday (7).
+func isoWeekday(tim time.Time) int {
+	return (int(tim.Weekday())+6)%7 + 1
+}
+
+func (r *parsed) value(f field, def int) int {
+	if v, ok := r.values[f]; ok {
+		return v
+	}
+	return def
+}
+
+func (r *parsed) hour() (int, error) {
+	if v, ok := r.values[fieldHourOfDay]; ok {
+		if v > 23 {
+			return 0, fmt.Errorf("hour %d is out of range [0, 23]", v)
+		}
+		return v, nil
+	}
+	if v, ok := r.values[fieldClockHourOfDay]; ok {
+		if v < 1 || v > 24 {
+			return 0, fmt.Errorf("hour %d is out of range [1, 24]", v)
+		}
+		return v % 24, nil
+	}
+	var halfDay = r.value(fieldHalfDay, 0)
+	if v, ok := r.values[fieldHourOfHalfDay]; ok {
+		if v > 11 {
+			return 0, fmt.Errorf("hour %d is out of range [0, 11]", v)
+		}
+		return halfDay*12 + v, nil
+	}
+	if v, ok := r.values[fieldClockHourOfHalfDay]; ok {
+		if v < 1 || v > 12 {
+			return 0, fmt.Errorf("hour %d is out of range [1, 12]", v)
+		}
+		return halfDay*12 + v%12, nil
+	}
+	return 0, nil
+}
+
+// precision returns the finest field which is parsed.
+func (r *parsed) precision() Precision {
+	var res = PrecisionUnknown
+	for f := range r.values {
+		var cur = fieldPrecisions[f]
+		if f == fieldFraction {
+			cur = fractionPrecision(r.digits)
+		}
+		if cur > res {
+			res = cur
+		}
+	}
+	return res
+}
+
+// daysInYear returns 366 for leap year of proleptic gregorian calendar, otherwise 365.
+func daysInYear(year int) int {
+	return time.Date(year, time.December, 31, 0, 0, 0, 0, time.UTC).YearDay()
+}
+
+func daysIn(month time.Month, year int) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+// format formats tim with pattern, tim should be in the time zone to print.
+func (p *pattern) format(tim time.Time, ctx *parseContext) (string, error) {
+	if p.dialect == PatternGo {
+		return tim.Format(goLayout(p.source)), nil
+	}
+	var sb = &strings.Builder{}
+	if err := p.formatElements(p.elements, tim, p.context(ctx), sb, map[field]bool{}); err != nil {
+		return "", fmt.Errorf("failed to format time with pattern: %s, %s", p.source, err)
+	}
+	return sb.String(), nil
+}
+
+// formatElements prints elements, optional section is skipped when one of
+// its fields is printed already, so `[ZZ][Z]` prints offset once.
+func (p *pattern) formatElements(elements []element, tim time.Time, ctx *parseContext, sb *strings.Builder, printed map[field]bool) error {
+	for _, e := range elements {
+		if e.field == fieldOptional {
+			if !hasPrinted(e.optional, printed) {
+				if err := p.formatElements(e.optional, tim, ctx, sb, printed); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+		if err := p.formatElement(e, tim, ctx, sb); err != nil {
+			return err
+		}
+		printed[e.field] = true
+	}
+	return nil
+}
+
+func hasPrinted(elements []element, printed map[field]bool) bool {
+	for _, e := range elements {
+		if (e.field != fieldLiteral && printed[e.field]) || (e.field == fieldOptional && hasPrinted(e.optional, printed)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *pattern) formatElement(e element, tim time.Time, ctx *parseContext, sb *strings.Builder) error {
+	var isoDay = isoWeekday(tim)
+	var weekYear, week = p.weekFields(ctx).weekOf(tim)
+	switch e.field {
+	case fieldLiteral:
+		sb.WriteString(e.literal)
+	case fieldYear, fieldWeekYear:
+		var year = tim.Year()
+		if e.field == fieldWeekYear {
+			year = weekYear
+		}
+		if e.count == 2 {
+			fmt.Fprintf(sb, "%02d", (year%100+100)%100)
+		} else {
+			writeYear(sb, e, year)
+		}
+	case fieldYearOfEra:
+		var year = tim.Year()
+		if year <= 0 {
+			// year of era counts 1 BC, 2 BC... before year 1
+			year = 1 - year
+		}
+		if e.count == 2 {
+			fmt.Fprintf(sb, "%02d", year%100)
+		} else {
+			writeNumber(sb, e, year)
+		}
+	case fieldWeek:
+		writeNumber(sb, e, week)
+	case fieldMonth:
+		if e.text {
+			sb.WriteString(localeName(e.count, int(tim.Month())-1, ctx.locale.months, ctx.locale.shortMonths))
+		} else {
+			writeNumber(sb, e, int(tim.Month()))
+		}
+	case fieldDay:
+		writeNumber(sb, e, tim.Day())
+	case fieldDayOfYear:
+		writeNumber(sb, e, tim.YearDay())
+	case fieldDayOfWeek:
+		if e.text {
+			sb.WriteString(localeName(e.count, isoDay-1, ctx.locale.days, ctx.locale.shortDays))
+		} else if e.localized {
+			writeNumber(sb, e, weekFieldsOf(ctx.firstDay()).dayOfWeek(tim))
+		} else {
+			writeNumber(sb, e, isoDay)
+		}
+	case fieldHalfDay:
+		sb.WriteString(ctx.locale.halfDays[tim.Hour()/12])
+	case fieldHourOfDay:
+		writeNumber(sb, e, tim.Hour())
+	case fieldClockHourOfDay:
+		writeNumber(sb, e, (tim.Hour()+23)%24+1)
+	case fieldHourOfHalfDay:
+		writeNumber(sb, e, tim.Hour()%12)
+	case fieldClockHourOfHalfDay:
+		writeNumber(sb, e, (tim.Hour()+11)%12+1)
+	case fieldMinute:
+		writeNumber(sb, e, tim.Minute())
+	case fieldSecond:
+		writeNumber(sb, e, tim.Second())
+	case fieldFraction:
+		var digits = fmt.Sprintf("%09d", tim.Nanosecond())
+		if e.count <= 9 {
+			digits = digits[:e.count]
+		}
+		sb.WriteString(digits)
+	case fieldNano:
+		writeNumber(sb, e, tim.Nanosecond())
+	case fieldOffset:
+		sb.WriteString(formatOffset(tim, e.offset))
+	case fieldZoneID:
+		sb.WriteString(tim.Location().String())
+	case fieldZoneName:
+		sb.WriteString(formatZoneName(tim))
+	}
+	return nil
+}
+
+// writeYear prints signed year, `+` is printed when year of 4 or more letters exceeds the count,
+// like `+10000` of `yyyy`, and `-` is printed for year before 1 AD like `-0044`.
+func writeYear(sb *strings.Builder, e element, year int) {
+	if year < 0 {
+		sb.WriteByte('-')
+		year = -year
+	} else if e.count >= 4 && len(strconv.Itoa(year)) > e.count {
+		sb.WriteByte('+')
+	}
+	writeNumber(sb, e, year)
+}
+
+// writeNumber prints v padded to count of pattern letters.
+func writeNumber(sb *strings.Builder, e element, v int) {
+	if e.space {
+		fmt.Fprintf(sb, "%*d", e.count, v)
+	} else {
+		fmt.Fprintf(sb, "%0*d", e.count, v)
+	}
+}
+
+// localeName returns full name for 4 or more pattern letters, otherwise abbreviation.
+func localeName(count, index int, names, shortNames []string) string {
+	if count >= 4 {
+		return names[index]
+	}
+	return shortNames[index]
+}
+
+func formatOffset(tim time.Time, style offsetStyle) string {
+	var _, offset = tim.Zone()
+	if offset == 0 && style.zulu {
+		return "Z"
+	}
+	var sign = '+'
+	if offset < 0 {
+		sign, offset = '-', -offset
+	}
+	var hour, minute = offset / 3600, offset % 3600 / 60
+	if !style.minutes && minute == 0 {
+		return fmt.Sprintf("%c%02d", sign, hour)
+	} else if style.colon {
+		return fmt.Sprintf("%c%02d:%02d", sign, hour, minute)
+	}
+	return fmt.Sprintf("%c%02d%02d", sign, hour, minute)
+}
@@ -0,0 +1,712 @@
+package datemath_parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPattern_parse(t *testing.T) {
+	var shanghai, _ = time.LoadLocation("Asia/Shanghai")
+	tests := []struct {
+		name    string
+		pattern string
+		dialect PatternDialect
+		value   string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:    "TestPattern_parse01",
+			pattern: "uuuu-MM-dd'T'HH:mm:ssXXX",
+			dialect: PatternJava,
+			value:   "2021-05-10T10:00:00+02:00",
+			want:    time.Date(2021, 5, 10, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "TestPattern_parse02",
+			pattern: "uuuu-MM-dd'T'HH:mm:ssXXX",
+			dialect: PatternJava,
+			value:   "2021-05-10T10:00:00Z",
+			want:    time.Date(2021, 5, 10, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "TestPattern_parse03",
+			pattern: "yyyy-MM-dd'T'HH:mm:ss.SSSSSSSSSX",
+			dialect: PatternJava,
+			value:   "2021-05-10T10:00:00.123456789-03",
+			want:    time.Date(2021, 5, 10, 13, 0, 0, 123456789, time.UTC),
+		},
+		{
+			name:    "TestPattern_parse04",
+			pattern: "yyyy-MM-dd'T'HH:mm:ss.SSS",
+			dialect: PatternJava,
+			value:   "2021-05-10T10:00:00.12",
+			wantErr: true,
+		},
+		{
+			name:    "TestPattern_parse05",
+			pattern: "uuuu-MM-dd HH:mm VV",
+			dialect: PatternJava,
+			value:   "2021-05-10 10:00 Asia/Shanghai",
+			want:    time.Date(2021, 5, 10, 10, 0, 0, 0, shanghai),
+		},
+		{
+			name:    "TestPattern_parse06",
+			pattern: "dd MMM uuuu 'at' hh:mm a",
+			dialect: PatternJava,
+			value:   "10 May 2021 at 02:30 PM",
+			want:    time.Date(2021, 5, 10, 14, 30, 0, 0, time.UTC),
+		},
+		{
+			name:    "TestPattern_parse07",
+			pattern: "uuuuMMddHHmmss",
+			dialect: PatternJava,
+			value:   "20210510100000",
+			want:    time.Date(2021, 5, 10, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "TestPattern_parse08",
+			pattern: "''uuuu''",
+			dialect: PatternJava,
+			value:   "'2021'",
+			want:    time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "TestPattern_parse09",
+			pattern: "EEEE, d MMMM yyyy",
+			dialect: PatternJoda,
+			value:   "Monday, 10 May 2021",
+			want:    time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "TestPattern_parse10",
+			pattern: "EEEE, d MMMM yyyy",
+			dialect: PatternJoda,
+			value:   "Tuesday, 10 May 2021",
+			wantErr: true,
+		},
+		{
+			name:    "TestPattern_parse11",
+			pattern: "yyyy-MM-ddTHH:mm:ss.SSSZ",
+			dialect: PatternJoda,
+			value:   "2021-05-10T10:00:00.1+0800",
+			want:    time.Date(2021, 5, 10, 2, 0, 0, 100000000, time.UTC),
+		},
+		{
+			name:    "TestPattern_parse12",
+			pattern: "yyyy-MM-dd HH:mm:ss ZZ",
+			dialect: PatternJoda,
+			value:   "2021-05-10 10:00:00 -03:30",
+			want:    time.Date(2021, 5, 10, 13, 30, 0, 0, time.UTC),
+		},
+		{
+			name:    "TestPattern_parse13",
+			pattern: "yyyy-MM-dd",
+			dialect: PatternJoda,
+			value:   "2021-04-31",
+			wantErr: true,
+		},
+		{
+			name:    "TestPattern_parse14",
+			pattern: "yyyy-MM-dd HH:mm",
+			dialect: PatternJoda,
+			value:   "2021-04-30 24:00",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pat, err = compilePattern(tt.pattern, tt.dialect)
+			if err != nil {
+				t.Fatalf("failed to compile pattern: %s, err: %+v", tt.pattern, err)
+			}
+			got, _, err := pat.parse(tt.value, &parseContext{loc: time.UTC, locale: englishLocale})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("pattern.parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("pattern.parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPattern_compile(t *testing.T) {
+	tests := []struct {
+		name    string
+		pattern string
+		dialect PatternDialect
+		wantErr bool
+	}{
+		{name: "TestPattern_compile01", pattern: "uuuu-MM-ddTHH", dialect: PatternJava, wantErr: true},
+		{name: "TestPattern_compile02", pattern: "uuuu-MM-dd'T'HH", dialect: PatternJava},
+		{name: "TestPattern_compile03", pattern: "yyyy-MM-ddTHH", dialect: PatternJoda},
+		{name: "TestPattern_compile04", pattern: "yyyy-MM-dd'T", dialect: PatternJoda, wantErr: true},
+		{name: "TestPattern_compile05", pattern: "uuuu-MM-dd ZZZZ", dialect: PatternJava, wantErr: true},
+		{name: "TestPattern_compile06", pattern: "uuuu-MM-dd V", dialect: PatternJava, wantErr: true},
+		{name: "TestPattern_compile07", pattern: "yyyy-MM-dd G", dialect: PatternJoda, wantErr: true},
+		{name: "TestPattern_compile08", pattern: "CC yy", dialect: PatternJoda, wantErr: true},
+		{name: "TestPattern_compile09", pattern: "yyyy-MM-dd 'G'", dialect: PatternJoda},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := compilePattern(tt.pattern, tt.dialect); (err != nil) != tt.wantErr {
+				t.Errorf("compilePattern() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestDateMathParser_patternDialect(t *testing.T) {
+	tests := []struct {
+		name    string
+		opts    []DateMathParserOption
+		expr    string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "TestDateMathParser_patternDialect01",
+			opts: []DateMathParserOption{WithFormat([]string{"uuuu-MM-dd'T'HH:mm:ssXXX"}), WithPatternDialect(PatternJava)},
+			expr: "2021-05-10T10:00:00+02:00||+1d",
+			want: time.Date(2021, 5, 11, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "TestDateMathParser_patternDialect02",
+			opts: []DateMathParserOption{WithFormat([]string{"java:uuuu-MM-dd'T'HH:mm:ssXXX"})},
+			expr: "2021-05-10T10:00:00Z",
+			want: time.Date(2021, 5, 10, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "TestDateMathParser_patternDialect03",
+			opts: []DateMathParserOption{WithFormat([]string{"date_hour_minute"}), WithPatternDialect(PatternJava)},
+			expr: "2021-05-10T10:00",
+			want: time.Date(2021, 5, 10, 10, 0, 0, 0, time.UTC),
+		},
+		{
+			name: "TestDateMathParser_patternDialect04",
+			opts: []DateMathParserOption{WithFormat([]string{"date_hour_minute"}), WithTimeZone("+08:00")},
+			expr: "2021-05-10T10:00",
+			want: time.Date(2021, 5, 10, 2, 0, 0, 0, time.UTC),
+		},
+		{
+			name:    "TestDateMathParser_patternDialect05",
+			opts:    []DateMathParserOption{WithFormat([]string{"uuuu-MM-ddTHH"}), WithPatternDialect(PatternJava)},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p, err = NewDateMathParser(tt.opts...)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("NewDateMathParser() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got, err := p.Parse(tt.expr); err != nil {
+				t.Errorf("DateMathParser.Parse() error = %v", err)
+			} else if !got.Equal(tt.want) {
+				t.Errorf("DateMathParser.Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	// Formats changed after creating parser are compiled again, even if new format ends with the former one
+	var p, _ = NewDateMathParser(WithFormat([]string{"yyyy-MM-dd"}))
+	p.Formats[0] = "java:yyyy-MM-dd"
+	if got, err := p.Parse("2021-5-1"); err == nil {
+		t.Errorf("DateMathParser.Parse() = %v, want error of java pattern", got)
+	}
+}
+
+func TestPattern_optional(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		dialect   PatternDialect
+		value     string
+		want      time.Time
+		precision Precision
+		wantErr   bool
+	}{
+		{
+			name:      "TestPattern_optional01",
+			pattern:   "uuuu-MM-dd['T'HH:mm[:ss]]",
+			dialect:   PatternJava,
+			value:     "2021-05-10",
+			want:      time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC),
+			precision: PrecisionDay,
+		},
+		{
+			name:      "TestPattern_optional02",
+			pattern:   "uuuu-MM-dd['T'HH:mm[:ss]]",
+			dialect:   PatternJava,
+			value:     "2021-05-10T10:30",
+			want:      time.Date(2021, 5, 10, 10, 30, 0, 0, time.UTC),
+			precision: PrecisionMinute,
+		},
+		{
+			name:      "TestPattern_optional03",
+			pattern:   "uuuu-MM-dd['T'HH:mm[:ss]]",
+			dialect:   PatternJava,
+			value:     "2021-05-10T10:30:15",
+			want:      time.Date(2021, 5, 10, 10, 30, 15, 0, time.UTC),
+			precision: PrecisionSecond,
+		},
+		{
+			name:    "TestPattern_optional04",
+			pattern: "uuuu-MM-dd['T'HH:mm[:ss]]",
+			dialect: PatternJava,
+			value:   "2021-05-10T10",
+			wantErr: true,
+		},
+		{
+			name:    "TestPattern_optional05",
+			pattern: "uuuu-MM-dd['T'HH:mm",
+			dialect: PatternJava,
+			wantErr: true,
+		},
+		{
+			name:    "TestPattern_optional06",
+			pattern: "uuuu-MM-dd]",
+			dialect: PatternJava,
+			wantErr: true,
+		},
+		{
+			name:      "TestPattern_optional07",
+			pattern:   "yyyy-MM-dd['['HH']']",
+			dialect:   PatternJoda,
+			value:     "2021-05-10[10]",
+			want:      time.Date(2021, 5, 10, 10, 0, 0, 0, time.UTC),
+			precision: PrecisionHour,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pat, err = compilePattern(tt.pattern, tt.dialect)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("failed to compile pattern: %s, err: %+v", tt.pattern, err)
+				}
+				return
+			}
+			got, precision, err := pat.parse(tt.value, &parseContext{loc: time.UTC, locale: englishLocale})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("pattern.parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && (!got.Equal(tt.want) || precision != tt.precision) {
+				t.Errorf("pattern.parse() = %v, %v, want %v, %v", got, precision, tt.want, tt.precision)
+			}
+		})
+	}
+}
+
+func TestDateMathParser_dateOptionalTime(t *testing.T) {
+	tests := []struct {
+		name      string
+		format    string
+		expr      string
+		want      time.Time
+		precision Precision
+		wantErr   bool
+	}{
+		{
+			name:      "TestDateMathParser_dateOptionalTime01",
+			format:    STRICT_DATE_OPTIONAL_TIME,
+			expr:      "2021",
+			want:      time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC),
+			precision: PrecisionYear,
+		},
+		{
+			name:      "TestDateMathParser_dateOptionalTime02",
+			format:    STRICT_DATE_OPTIONAL_TIME,
+			expr:      "2021-05-10",
+			want:      time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC),
+			precision: PrecisionDay,
+		},
+		{
+			name:      "TestDateMathParser_dateOptionalTime03",
+			format:    STRICT_DATE_OPTIONAL_TIME,
+			expr:      "2021-05-10T10:00",
+			want:      time.Date(2021, 5, 10, 10, 0, 0, 0, time.UTC),
+			precision: PrecisionMinute,
+		},
+		{
+			name:      "TestDateMathParser_dateOptionalTime04",
+			format:    STRICT_DATE_OPTIONAL_TIME,
+			expr:      "2021-05-10T10:00:00Z",
+			want:      time.Date(2021, 5, 10, 10, 0, 0, 0, time.UTC),
+			precision: PrecisionSecond,
+		},
+		{
+			name:      "TestDateMathParser_dateOptionalTime05",
+			format:    STRICT_DATE_OPTIONAL_TIME,
+			expr:      "2021-05-10T10:00:00.123+08:00",
+			want:      time.Date(2021, 5, 10, 2, 0, 0, 123000000, time.UTC),
+			precision: PrecisionMillisecond,
+		},
+		{
+			name:      "TestDateMathParser_dateOptionalTime06",
+			format:    STRICT_DATE_OPTIONAL_TIME,
+			expr:      "2021-05-10T10:00:00,123-0330",
+			want:      time.Date(2021, 5, 10, 13, 30, 0, 123000000, time.UTC),
+			precision: PrecisionMillisecond,
+		},
+		{
+			name:      "TestDateMathParser_dateOptionalTime07",
+			format:    STRICT_DATE_OPTIONAL_TIME,
+			expr:      "2021-05-10T10Z",
+			want:      time.Date(2021, 5, 10, 10, 0, 0, 0, time.UTC),
+			precision: PrecisionHour,
+		},
+		{
+			name:    "TestDateMathParser_dateOptionalTime08",
+			format:  STRICT_DATE_OPTIONAL_TIME,
+			expr:    "2021-05-10T10:00.123",
+			wantErr: true,
+		},
+		{
+			name:      "TestDateMathParser_dateOptionalTime09",
+			format:    DATE_OPTIONAL_TIME,
+			expr:      "2021-05-10T10:00.123",
+			want:      time.Date(2021, 5, 10, 10, 0, 0, 123000000, time.UTC),
+			precision: PrecisionMillisecond,
+		},
+		{
+			name:    "TestDateMathParser_dateOptionalTime10",
+			format:  STRICT_DATE_OPTIONAL_TIME,
+			expr:    "2021-05-10 10:00:00",
+			wantErr: true,
+		},
+		{
+			name:      "TestDateMathParser_dateOptionalTime11",
+			format:    STRICT_DATE_OPTIONAL_TIME_NANOS,
+			expr:      "2021-05-10T10:00:00.1Z",
+			want:      time.Date(2021, 5, 10, 10, 0, 0, 100000000, time.UTC),
+			precision: PrecisionMillisecond,
+		},
+		{
+			name:      "TestDateMathParser_dateOptionalTime12",
+			format:    STRICT_DATE_OPTIONAL_TIME_NANOS,
+			expr:      "2021-05-10T10:00:00.1234Z",
+			want:      time.Date(2021, 5, 10, 10, 0, 0, 123400000, time.UTC),
+			precision: PrecisionMicrosecond,
+		},
+		{
+			name:      "TestDateMathParser_dateOptionalTime13",
+			format:    STRICT_DATE_OPTIONAL_TIME_NANOS,
+			expr:      "2021-05-10T10:00:00.123456789Z",
+			want:      time.Date(2021, 5, 10, 10, 0, 0, 123456789, time.UTC),
+			precision: PrecisionNanosecond,
+		},
+		{
+			name:    "TestDateMathParser_dateOptionalTime14",
+			format:  STRICT_DATE_OPTIONAL_TIME,
+			expr:    "2021-5-1",
+			wantErr: true,
+		},
+		{
+			name:    "TestDateMathParser_dateOptionalTime15",
+			format:  STRICT_DATE_OPTIONAL_TIME,
+			expr:    "2021-05-1",
+			wantErr: true,
+		},
+		{
+			name:    "TestDateMathParser_dateOptionalTime16",
+			format:  STRICT_DATE_OPTIONAL_TIME,
+			expr:    "2021-05-01T1:2",
+			wantErr: true,
+		},
+		{
+			name:    "TestDateMathParser_dateOptionalTime17",
+			format:  STRICT_DATE_OPTIONAL_TIME,
+			expr:    "2021-05-01T01:02:3",
+			wantErr: true,
+		},
+		{
+			name:    "TestDateMathParser_dateOptionalTime18",
+			format:  STRICT_DATE_OPTIONAL_TIME_NANOS,
+			expr:    "2021-05-01T1:02",
+			wantErr: true,
+		},
+		{
+			name:    "TestDateMathParser_dateOptionalTime19",
+			format:  STRICT_DATE,
+			expr:    "2021-05-1",
+			wantErr: true,
+		},
+		{
+			name:    "TestDateMathParser_dateOptionalTime20",
+			format:  STRICT_YEAR_MONTH,
+			expr:    "2021-5",
+			wantErr: true,
+		},
+		{
+			name:    "TestDateMathParser_dateOptionalTime21",
+			format:  STRICT_HOUR_MINUTE,
+			expr:    "1:02",
+			wantErr: true,
+		},
+		{
+			name:    "TestDateMathParser_dateOptionalTime22",
+			format:  STRICT_DATE_TIME_NO_MILLIS,
+			expr:    "2021-05-01T01:02:3Z",
+			wantErr: true,
+		},
+		{
+			name:    "TestDateMathParser_dateOptionalTime23",
+			format:  STRICT_WEEK_DATE,
+			expr:    "2021-W1-1",
+			wantErr: true,
+		},
+		{
+			name:    "TestDateMathParser_dateOptionalTime24",
+			format:  STRICT_ORDINAL_DATE,
+			expr:    "2021-32",
+			wantErr: true,
+		},
+		{
+			name:      "TestDateMathParser_dateOptionalTime25",
+			format:    DATE_OPTIONAL_TIME,
+			expr:      "2021-5-1T1:2",
+			want:      time.Date(2021, 5, 1, 1, 2, 0, 0, time.UTC),
+			precision: PrecisionMinute,
+		},
+		{
+			name:      "TestDateMathParser_dateOptionalTime26",
+			format:    STRICT_DATE_OPTIONAL_TIME,
+			expr:      "+10000-01-01",
+			want:      time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC),
+			precision: PrecisionDay,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p, _ = NewDateMathParser(WithFormat([]string{tt.format}))
+			got, err := p.ParseDetailed(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DateMathParser.ParseDetailed() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && (!got.Time.Equal(tt.want) || got.Precision != tt.precision) {
+				t.Errorf("DateMathParser.ParseDetailed() = %v, %v, want %v, %v", got.Time, got.Precision, tt.want, tt.precision)
+			}
+		})
+	}
+}
+
+func TestPattern_fraction(t *testing.T) {
+	tests := []struct {
+		name      string
+		pattern   string
+		dialect   PatternDialect
+		lenient   bool
+		value     string
+		want      time.Time
+		precision Precision
+		wantErr   bool
+	}{
+		{
+			name:      "TestPattern_fraction01",
+			pattern:   "uuuu-MM-dd'T'HH:mm:ss.SSSX",
+			dialect:   PatternJava,
+			lenient:   true,
+			value:     "2021-05-10T10:00:00.1Z",
+			want:      time.Date(2021, 5, 10, 10, 0, 0, 100000000, time.UTC),
+			precision: PrecisionMillisecond,
+		},
+		{
+			name:      "TestPattern_fraction02",
+			pattern:   "uuuu-MM-dd'T'HH:mm:ss.SSSX",
+			dialect:   PatternJava,
+			lenient:   true,
+			value:     "2021-05-10T10:00:00.123456789Z",
+			want:      time.Date(2021, 5, 10, 10, 0, 0, 123456789, time.UTC),
+			precision: PrecisionNanosecond,
+		},
+		{
+			name:    "TestPattern_fraction03",
+			pattern: "uuuu-MM-dd'T'HH:mm:ss.SSSX",
+			dialect: PatternJava,
+			value:   "2021-05-10T10:00:00.1234Z",
+			wantErr: true,
+		},
+		{
+			name:    "TestPattern_fraction04",
+			pattern: "uuuu-MM-dd'T'HH:mm:ss.SSSX",
+			dialect: PatternJava,
+			lenient: true,
+			value:   "2021-05-10T10:00:00.1234567890Z",
+			wantErr: true,
+		},
+		{
+			name:      "TestPattern_fraction05",
+			pattern:   "HHmmssSSS",
+			dialect:   PatternJoda,
+			value:     "100000123",
+			want:      time.Date(0, 1, 1, 10, 0, 0, 123000000, time.UTC),
+			precision: PrecisionMillisecond,
+		},
+		{
+			name:      "TestPattern_fraction06",
+			pattern:   "ss.SSS",
+			dialect:   PatternJoda,
+			value:     "05.123456",
+			want:      time.Date(0, 1, 1, 0, 0, 5, 123456000, time.UTC),
+			precision: PrecisionMicrosecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var pat, _ = compilePattern(tt.pattern, tt.dialect)
+			got, precision, err := pat.parse(tt.value, &parseContext{loc: time.UTC, lenientFraction: tt.lenient, locale: englishLocale})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("pattern.parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && (!got.Equal(tt.want) || precision != tt.precision) {
+				t.Errorf("pattern.parse() = %v, %v, want %v, %v", got, precision, tt.want, tt.precision)
+			}
+		})
+	}
+}
+
+func TestDateMathParser_weekDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		expr    string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "TestDateMathParser_weekDate01", format: WEEK_DATE, expr: "2020-W53-5", want: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_weekDate02", format: WEEK_DATE, expr: "2021-W01-1", want: time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_weekDate03", format: WEEK_DATE, expr: "2019-W01-1", want: time.Date(2018, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_weekDate04", format: WEEK_DATE, expr: "2020-W01-1", want: time.Date(2019, 12, 30, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_weekDate05", format: WEEK_DATE, expr: "2015-W53-7", want: time.Date(2016, 1, 3, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_weekDate06", format: WEEK_DATE, expr: "2021-W52-7", want: time.Date(2022, 1, 2, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_weekDate07", format: WEEK_DATE, expr: "2021-W53-1", wantErr: true},
+		{name: "TestDateMathParser_weekDate08", format: WEEK_DATE, expr: "2021-W00-1", wantErr: true},
+		{name: "TestDateMathParser_weekDate09", format: WEEK_DATE, expr: "2021-W10-8", wantErr: true},
+		{name: "TestDateMathParser_weekDate10", format: WEEK_DATE, expr: "2021-10-1", wantErr: true},
+		{name: "TestDateMathParser_weekDate11", format: BASIC_WEEK_DATE, expr: "2020W535", want: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_weekDate12", format: WEEKYEAR_WEEK, expr: "2021-W05", want: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_weekDate13", format: WEEKYEAR, expr: "2021", want: time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_weekDate14", format: WEEK_DATE_TIME_NO_MILLIS, expr: "2009-W53-7T10:00:00Z", want: time.Date(2010, 1, 3, 10, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_weekDate15", format: "java:YYYY-'W'ww-E", expr: "2020-W53-Fri", want: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p, _ = NewDateMathParser(WithFormat([]string{tt.format}))
+			got, err := p.Parse(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DateMathParser.Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("DateMathParser.Parse() = %v, want %v", got, tt.want)
+			}
+			if tt.wantErr || tt.format == WEEKYEAR || tt.format == WEEKYEAR_WEEK {
+				return
+			}
+			if s, err := p.Format(got, tt.format); err != nil || s != tt.expr {
+				t.Errorf("DateMathParser.Format() = %v, %v, want %v", s, err, tt.expr)
+			}
+		})
+	}
+}
+
+func TestDateMathParser_ordinalDate(t *testing.T) {
+	tests := []struct {
+		name    string
+		format  string
+		expr    string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "TestDateMathParser_ordinalDate01", format: ORDINAL_DATE, expr: "2020-366", want: time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_ordinalDate02", format: ORDINAL_DATE, expr: "2021-366", wantErr: true},
+		{name: "TestDateMathParser_ordinalDate03", format: ORDINAL_DATE, expr: "2021-365", want: time.Date(2021, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_ordinalDate04", format: ORDINAL_DATE, expr: "2021-001", want: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_ordinalDate05", format: ORDINAL_DATE, expr: "2021-000", wantErr: true},
+		{name: "TestDateMathParser_ordinalDate06", format: ORDINAL_DATE, expr: "2020-060", want: time.Date(2020, 2, 29, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_ordinalDate07", format: ORDINAL_DATE, expr: "1900-366", wantErr: true},
+		{name: "TestDateMathParser_ordinalDate08", format: BASIC_ORDINAL_DATE, expr: "2021032", want: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_ordinalDate09", format: BASIC_ORDINAL_DATE_TIME_NO_MILLIS, expr: "2021100T101500Z", want: time.Date(2021, 4, 10, 10, 15, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_ordinalDate10", format: ORDINAL_DATE_TIME, expr: "2000-366T23:59:59.999Z", want: time.Date(2000, 12, 31, 23, 59, 59, 999000000, time.UTC)},
+		{name: "TestDateMathParser_ordinalDate11", format: "yyyy-DDD-MM", expr: "2021-032-02", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p, _ = NewDateMathParser(WithFormat([]string{tt.format}))
+			got, err := p.Parse(tt.expr)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("DateMathParser.Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("DateMathParser.Parse() = %v, want %v", got, tt.want)
+			}
+			if s, err := p.Format(got, tt.format); err != nil || s != tt.expr {
+				t.Errorf("DateMathParser.Format() = %v, %v, want %v", s, err, tt.expr)
+			}
+		})
+	}
+}
+
+func TestDateMathParser_zoneOffset(t *testing.T) {
+	var paris, _ = time.LoadLocation("Europe/Paris")
+	tests := []struct {
+		name     string
+		format   string
+		timeZone string
+		expr     string
+		want     time.Time
+		wantLoc  *time.Location
+		wantErr  bool
+	}{
+		{name: "TestDateMathParser_zoneOffset01", format: DATE_TIME_NO_MILLIS, expr: "2021-05-10T10:00:00Z", want: time.Date(2021, 5, 10, 10, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_zoneOffset02", format: DATE_TIME_NO_MILLIS, expr: "2021-05-10T10:00:00+08", want: time.Date(2021, 5, 10, 2, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_zoneOffset03", format: DATE_TIME_NO_MILLIS, expr: "2021-05-10T10:00:00+0800", want: time.Date(2021, 5, 10, 2, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_zoneOffset04", format: DATE_TIME_NO_MILLIS, expr: "2021-05-10T10:00:00+08:00", want: time.Date(2021, 5, 10, 2, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_zoneOffset05", format: DATE_TIME_NO_MILLIS, expr: "2021-05-10T10:00:00-03:30", want: time.Date(2021, 5, 10, 13, 30, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_zoneOffset06", format: DATE_TIME_NO_MILLIS, expr: "2021-05-10T10:00:00+02:00[Europe/Paris]", want: time.Date(2021, 5, 10, 8, 0, 0, 0, time.UTC), wantLoc: paris},
+		{name: "TestDateMathParser_zoneOffset07", format: STRICT_DATE_OPTIONAL_TIME, expr: "2021-05-10T10:00:00.123+02:00[Europe/Paris]", want: time.Date(2021, 5, 10, 8, 0, 0, 123000000, time.UTC), wantLoc: paris},
+		{name: "TestDateMathParser_zoneOffset08", format: DATE_TIME_NO_MILLIS, timeZone: "Asia/Shanghai", expr: "2021-05-10T10:00:00-03:30", want: time.Date(2021, 5, 10, 13, 30, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_zoneOffset09", format: STRICT_DATE_OPTIONAL_TIME, timeZone: "Asia/Shanghai", expr: "2021-05-10T10:00:00", want: time.Date(2021, 5, 10, 2, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_zoneOffset10", format: DATE_TIME_NO_MILLIS, expr: "2021-05-10T10:00:00+08:00[Mars/Olympus]", wantErr: true},
+		{name: "TestDateMathParser_zoneOffset11", format: DATE_TIME_NO_MILLIS, expr: "2021-05-10T10:00:00+08:00[Europe/Paris", wantErr: true},
+		{name: "TestDateMathParser_zoneOffset12", format: DATE_TIME_NO_MILLIS, expr: "2021-05-10T10:00:00+19:00", wantErr: true},
+		{name: "TestDateMathParser_zoneOffset13", format: "java:uuuu-MM-dd'T'HH:mm:ssXXX", expr: "2021-05-10T10:00:00+08", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts = []DateMathParserOption{WithFormat([]string{tt.format})}
+			if tt.timeZone != "" {
+				opts = append(opts, WithTimeZone(tt.timeZone))
+			}
+			var p, _ = NewDateMathParser(opts...)
+			var pat, _ = p.pattern(0)
+			got, _, err := pat.parse(tt.expr, p.parseContext())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("pattern.parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			if !got.Equal(tt.want) {
+				t.Errorf("pattern.parse() = %v, want %v", got, tt.want)
+			}
+			if tt.wantLoc != nil && got.Location().String() != tt.wantLoc.String() {
+				t.Errorf("location of pattern.parse() = %v, want %v", got.Location(), tt.wantLoc)
+			}
+		})
+	}
+}
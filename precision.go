@@ -0,0 +1,129 @@
+package datemath_parser
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/araddon/dateparse"
+)
+
+// Precision is the finest date field present in a time literal.
+type Precision int
+
+const (
+	PrecisionUnknown Precision = iota
+	PrecisionYear
+	PrecisionMonth
+	PrecisionWeek
+	PrecisionDay
+	PrecisionHour
+	PrecisionMinute
+	PrecisionSecond
+	PrecisionMillisecond
+	PrecisionMicrosecond
+	PrecisionNanosecond
+)
+
+var precisionNames = map[Precision]string{
+	PrecisionUnknown:     "unknown",
+	PrecisionYear:        "year",
+	PrecisionMonth:       "month",
+	PrecisionWeek:        "week",
+	PrecisionDay:         "day",
+	PrecisionHour:        "hour",
+	PrecisionMinute:      "minute",
+	PrecisionSecond:      "second",
+	PrecisionMillisecond: "millisecond",
+	PrecisionMicrosecond: "microsecond",
+	PrecisionNanosecond:  "nanosecond",
+}
+
+func (p Precision) String() string {
+	return precisionNames[p]
+}
+
+// fractionPrecision returns precision of fraction of second with n digits.
+func fractionPrecision(n int) Precision {
+	if n <= 3 {
+		return PrecisionMillisecond
+	} else if n <= 6 {
+		return PrecisionMicrosecond
+	} else {
+		return PrecisionNanosecond
+	}
+}
+
+var layoutFraction = regexp.MustCompile(`[.,](0+|9+)`)
+
+// layoutTokens is ordered that longer go layout tokens are removed before shorter ones.
+var layoutTokens = []struct {
+	token     string
+	precision Precision
+}{
+	{"Z07:00", PrecisionUnknown}, {"-07:00", PrecisionUnknown}, {"Z0700", PrecisionUnknown},
+	{"-0700", PrecisionUnknown}, {"-07", PrecisionUnknown}, {"MST", PrecisionUnknown},
+	{"2006", PrecisionYear}, {"January", PrecisionMonth}, {"Jan", PrecisionMonth},
+	{"Monday", PrecisionDay}, {"Mon", PrecisionDay}, {"PM", PrecisionUnknown}, {"pm", PrecisionUnknown},
+	{"15", PrecisionHour}, {"002", PrecisionDay}, {"05", PrecisionSecond}, {"04", PrecisionMinute},
+	{"03", PrecisionHour}, {"02", PrecisionDay}, {"01", PrecisionMonth}, {"06", PrecisionYear},
+	{"_2", PrecisionDay}, {"5", PrecisionSecond}, {"4", PrecisionMinute}, {"3", PrecisionHour},
+	{"2", PrecisionDay}, {"1", PrecisionMonth},
+}
+
+// layoutPrecision returns the finest field of a go time layout.
+func layoutPrecision(layout string) Precision {
+	var res = PrecisionUnknown
+	for _, s := range layoutFraction.FindAllStringSubmatch(layout, -1) {
+		if cur := fractionPrecision(len(s[1])); cur > res {
+			res = cur
+		}
+	}
+	layout = layoutFraction.ReplaceAllString(layout, "")
+	for _, t := range layoutTokens {
+		if strings.Contains(layout, t.token) {
+			layout = strings.ReplaceAll(layout, t.token, " ")
+			if t.precision > res {
+				res = t.precision
+			}
+		}
+	}
+	return res
+}
+
+// anyPrecision returns precision of expr, which is parsed without given formats by parseAny.
+func (p *DateMathParser) anyPrecision(expr string) Precision {
+	if isDigits(expr) {
+		// digits are treated as epoch, whose unit is decided by length
+		if len(expr) <= 10 {
+			return PrecisionSecond
+		} else if len(expr) <= 13 {
+			return PrecisionMillisecond
+		} else if len(expr) <= 16 {
+			return PrecisionMicrosecond
+		} else {
+			return PrecisionNanosecond
+		}
+	}
+	// guess layout in the same order of day and month as parseAny
+	expr = dottedDate.ReplaceAllString(expr, "$1/$2/$3")
+	var layout, err = dateparse.ParseFormat(expr, dateparse.PreferMonthFirst(!p.DayFirst))
+	if err != nil {
+		layout, err = dateparse.ParseFormat(expr, dateparse.PreferMonthFirst(p.DayFirst))
+	}
+	if err == nil {
+		return layoutPrecision(layout)
+	}
+	return PrecisionUnknown
+}
+
+func isDigits(s string) bool {
+	if len(s) == 0 {
+		return false
+	}
+	for _, c := range s {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+package datemath_parser
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// FormatRegistry holds named formats used by WithFormat, it's safe for concurrent use,
+// so custom names like `company_log_ts` can be registered without mutating BuiltInFormat.
+type FormatRegistry struct {
+	mu      sync.RWMutex
+	formats map[string]registeredFormat
+	aliases map[string]string
+}
+
+type registeredFormat struct {
+	patterns []string
+	builtIn  bool // patterns of built-in format are joda syntax
+	options  patternOptions
+}
+
+// NewFormatRegistry creates a registry which contains all formats of BuiltInFormat.
+func NewFormatRegistry() *FormatRegistry {
+	var r = &FormatRegistry{
+		formats: make(map[string]registeredFormat, len(BuiltInFormat)),
+		aliases: map[string]string{},
+	}
+	for name, patterns := range BuiltInFormat {
+		r.formats[name] = registeredFormat{patterns: append([]string{}, patterns...), builtIn: true, options: builtInOptions(name)}
+	}
+	return r
+}
+
+// Register defines name as patterns, which replaces former format or alias of the name.
+// Patterns use dialect of parser unless they have prefix like `java:`.
+func (r *FormatRegistry) Register(name string, patterns ...string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.aliases, name)
+	r.formats[name] = registeredFormat{patterns: append([]string{}, patterns...)}
+}
+
+// Alias makes alias refer to the format or alias registered as name, alias of alias follows
+// the later change of the alias it refers to.
+func (r *FormatRegistry) Alias(alias, name string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var target = name
+	for {
+		if target == alias {
+			return fmt.Errorf("format: %s can't be alias of itself", alias)
+		}
+		var next, ok = r.aliases[target]
+		if !ok {
+			break
+		}
+		target = next
+	}
+	if _, ok := r.formats[target]; !ok {
+		return fmt.Errorf("format: %s is not registered", name)
+	}
+	delete(r.formats, alias)
+	r.aliases[alias] = name
+	return nil
+}
+
+// resolve follows chain of aliases to name of format, the chain has no cycle since Alias rejects it.
+func (r *FormatRegistry) resolve(name string) string {
+	for {
+		var target, ok = r.aliases[name]
+		if !ok {
+			return name
+		}
+		name = target
+	}
+}
+
+// Lookup returns patterns of name or alias.
+func (r *FormatRegistry) Lookup(name string) ([]string, bool) {
+	var f, ok = r.lookup(name)
+	return append([]string{}, f.patterns...), ok
+}
+
+func (r *FormatRegistry) lookup(name string) (registeredFormat, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var f, ok = r.formats[r.resolve(name)]
+	return f, ok
+}
+
+// Names returns sorted names and aliases of registry.
+func (r *FormatRegistry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	var names = make([]string, 0, len(r.formats)+len(r.aliases))
+	for name := range r.formats {
+		names = append(names, name)
+	}
+	for alias := range r.aliases {
+		names = append(names, alias)
+	}
+	sort.Strings(names)
+	return names
+}
@@ -0,0 +1,138 @@
+package datemath_parser
+
+import (
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFormatRegistry(t *testing.T) {
+	var r = NewFormatRegistry()
+	r.Register("company_log_ts", "yyyy/MM/dd HH:mm:ss", EPOCH_MILLIS)
+	if err := r.Alias("log_ts", "company_log_ts"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Alias("ts", "log_ts"); err != nil {
+		t.Fatal(err)
+	}
+	if err := r.Alias("missing_ts", "missing"); err == nil {
+		t.Errorf("expect error for alias of missing format")
+	}
+	var want = []string{"yyyy/MM/dd HH:mm:ss", EPOCH_MILLIS}
+	for _, name := range []string{"company_log_ts", "log_ts", "ts"} {
+		if got, ok := r.Lookup(name); !ok || !reflect.DeepEqual(got, want) {
+			t.Errorf("Lookup(%s) = %v, %v, want %v", name, got, ok, want)
+		}
+	}
+	if got, ok := r.Lookup(DATE); !ok || !reflect.DeepEqual(got, BuiltInFormat[DATE]) {
+		t.Errorf("Lookup(%s) = %v, %v, want %v", DATE, got, ok, BuiltInFormat[DATE])
+	}
+	if _, ok := r.Lookup("missing"); ok {
+		t.Errorf("Lookup(missing) should fail")
+	}
+	if _, ok := BuiltInFormat["company_log_ts"]; ok {
+		t.Errorf("Register shouldn't change BuiltInFormat")
+	}
+	// aliases follow the format or alias they refer to
+	r.Register("company_log_ts", DATE)
+	if err := r.Alias("log_ts", DATE_TIME); err != nil {
+		t.Fatal(err)
+	}
+	if got, ok := r.Lookup("ts"); !ok || !reflect.DeepEqual(got, BuiltInFormat[DATE_TIME]) {
+		t.Errorf("Lookup(ts) = %v, %v, want %v", got, ok, BuiltInFormat[DATE_TIME])
+	}
+	r.Register("log_ts", EPOCH_SECOND)
+	if got, ok := r.Lookup("ts"); !ok || !reflect.DeepEqual(got, []string{EPOCH_SECOND}) {
+		t.Errorf("Lookup(ts) = %v, %v, want %v", got, ok, []string{EPOCH_SECOND})
+	}
+	for _, alias := range []string{"log_ts", "ts"} {
+		if err := r.Alias(alias, "ts"); err == nil {
+			t.Errorf("expect error for cycle of alias %s", alias)
+		}
+	}
+}
+
+func TestDateMathParser_registry(t *testing.T) {
+	var r = NewFormatRegistry()
+	r.Register("company_log_ts", "yyyy/MM/dd HH:mm:ss")
+	r.Register("java_ts", "uuuu.MM.dd")
+	_ = r.Alias("log_ts", "company_log_ts")
+	tests := []struct {
+		name       string
+		opts       []DateMathParserOption
+		expr       string
+		want       time.Time
+		wantFormat string
+		wantErr    bool
+	}{
+		{
+			name:       "TestDateMathParser_registry01",
+			opts:       []DateMathParserOption{WithRegistry(r), WithFormat([]string{"log_ts"})},
+			expr:       "2021/05/10 10:20:30",
+			want:       time.Date(2021, 5, 10, 10, 20, 30, 0, time.UTC),
+			wantFormat: "log_ts",
+		},
+		{
+			name:       "TestDateMathParser_registry02",
+			opts:       []DateMathParserOption{WithFormat([]string{"company_log_ts", DATE}), WithRegistry(r)},
+			expr:       "2021-05-10",
+			want:       time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC),
+			wantFormat: DATE,
+		},
+		{
+			name:       "TestDateMathParser_registry03",
+			opts:       []DateMathParserOption{WithRegistry(r), WithPatternDialect(PatternJava), WithFormat([]string{"java_ts"})},
+			expr:       "2021.05.10",
+			want:       time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC),
+			wantFormat: "java_ts",
+		},
+		{
+			name:    "TestDateMathParser_registry04",
+			opts:    []DateMathParserOption{WithFormat([]string{"log_ts"})},
+			expr:    "2021/05/10 10:20:30",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p, err = NewDateMathParser(tt.opts...)
+			if err != nil {
+				if !tt.wantErr {
+					t.Errorf("NewDateMathParser() error = %v", err)
+				}
+				return
+			}
+			var res, perr = p.ParseDetailed(tt.expr)
+			if (perr != nil) != tt.wantErr {
+				t.Errorf("ParseDetailed() error = %v, wantErr %v", perr, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && (!res.Time.Equal(tt.want) || res.Format != tt.wantFormat) {
+				t.Errorf("ParseDetailed() = %v %s, want %v %s", res.Time, res.Format, tt.want, tt.wantFormat)
+			}
+		})
+	}
+}
+
+func TestFormatRegistry_concurrent(t *testing.T) {
+	var r = NewFormatRegistry()
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			r.Register("custom", "yyyy/MM/dd")
+			var p, err = NewDateMathParser(WithRegistry(r), WithFormat([]string{"custom"}))
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if _, err := p.Parse("2021/05/10"); err != nil {
+				t.Error(err)
+			}
+			_ = r.Names()
+		}(i)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,200 @@
+package datemath_parser
+
+import (
+	"time"
+)
+
+// precisionUnits maps precision of anchor literal to the unit used by implicit rounding.
+var precisionUnits = map[Precision]string{
+	PrecisionYear:   "y",
+	PrecisionMonth:  "M",
+	PrecisionWeek:   "w",
+	PrecisionDay:    "d",
+	PrecisionHour:   "h",
+	PrecisionMinute: "m",
+	PrecisionSecond: "s",
+}
+
+// location returns time zone of parser, parser created by struct literal may leave it nil.
+func (p *DateMathParser) location() *time.Location {
+	if p.TimeZone == nil {
+		return time.UTC
+	}
+	return p.TimeZone
+}
+
+// firstDayOfWeek returns the first day of week set by WithWeekStart, or the first day of locale
+// set by WithLocale or WithLocaleWeekStart, weeks start on monday like ElasticSearch by default.
+func (p *DateMathParser) firstDayOfWeek() time.Weekday {
+	if p.weekStart != nil {
+		return *p.weekStart
+	}
+	if p.hasLocaleWeekStart() {
+		if loc, ok := lookupLocale(p.Locale); ok {
+			return loc.firstDay
+		}
+		return englishLocale.firstDay
+	}
+	return time.Monday
+}
+
+// hasLocaleWeekStart reports whether weeks start on the first day of locale instead of monday.
+func (p *DateMathParser) hasLocaleWeekStart() bool {
+	return p.weekStartFromLocale || p.Locale != ""
+}
+
+// floorUnit returns start of the calendar unit containing tim in time zone of parser.
+func (p *DateMathParser) floorUnit(tim time.Time, unit string) time.Time {
+	var loc = p.location()
+	tim = tim.In(loc)
+	var year, month, day = tim.Date()
+	switch unit {
+	case "y":
+		return time.Date(year, time.January, 1, 0, 0, 0, 0, loc)
+	case "M":
+		return time.Date(year, month, 1, 0, 0, 0, 0, loc)
+	case "Q":
+		return time.Date(year, month-(month-1)%3, 1, 0, 0, 0, 0, loc)
+	case "fy", "fQ":
+		return p.floorFiscal(tim, unit)
+	case "w":
+		return time.Date(year, month, day-weekFieldsOf(p.firstDayOfWeek()).dayOfWeek(tim)+1, 0, 0, 0, 0, loc)
+	case "w0", "w1", "w2", "w3", "w4", "w5", "w6":
+		// weeks starting on the given day of week, like snap `@w1` of splunk
+		return time.Date(year, month, day-weekFieldsOf(time.Weekday(unit[1]-'0')).dayOfWeek(tim)+1, 0, 0, 0, 0, loc)
+	case "d":
+		return time.Date(year, month, day, 0, 0, 0, 0, loc)
+	case "h", "H":
+		return time.Date(year, month, day, tim.Hour(), 0, 0, 0, loc)
+	case "m":
+		return time.Date(year, month, day, tim.Hour(), tim.Minute(), 0, 0, loc)
+	case "s":
+		return time.Date(year, month, day, tim.Hour(), tim.Minute(), tim.Second(), 0, loc)
+	}
+	return tim
+}
+
+// nextUnit returns start of the calendar unit following the one which starts at tim.
+func (p *DateMathParser) nextUnit(tim time.Time, unit string) time.Time {
+	var year, month, day = tim.Date()
+	switch unit {
+	case "y":
+		return time.Date(year+1, month, day, 0, 0, 0, 0, tim.Location())
+	case "M":
+		return time.Date(year, month+1, day, 0, 0, 0, 0, tim.Location())
+	case "fy":
+		return time.Date(year, month+12, day, 0, 0, 0, 0, tim.Location())
+	case "Q", "fQ":
+		return time.Date(year, month+3, day, 0, 0, 0, 0, tim.Location())
+	case "w", "w0", "w1", "w2", "w3", "w4", "w5", "w6":
+		return time.Date(year, month, day+7, 0, 0, 0, 0, tim.Location())
+	case "d":
+		return time.Date(year, month, day+1, 0, 0, 0, 0, tim.Location())
+	}
+	return tim.Add(units[unit])
+}
+
+// roundUnit rounds tim down to start of unit, in round up mode it rounds to
+// the last millisecond of unit instead, as ElasticSearch does for `lte` bound.
+func (p *DateMathParser) roundUnit(tim time.Time, unit string) time.Time {
+	var res = p.floorUnit(tim, unit)
+	if p.RoundUp {
+		res = p.nextUnit(res, unit).Add(-time.Millisecond)
+	}
+	return res.UTC()
+}
+
+// multipleUnits are units supporting rounding with count like `/15m`, values tell whether
+// unit is fixed length, calendar units are aligned to multiples of months since year 0.
+var multipleUnits = map[string]bool{
+	"y": false,
+	"M": false,
+	"d": true,
+	"h": true,
+	"H": true,
+	"m": true,
+	"s": true,
+}
+
+// roundMultiple rounds tim down to start of bucket of op.Amount units in time zone of parser,
+// like fixed_interval of ElasticSearch date_histogram, buckets are shifted by RoundOffset.
+// In round up mode it rounds to the last millisecond of bucket instead.
+func (p *DateMathParser) roundMultiple(tim time.Time, op MathOp) (time.Time, error) {
+	var loc = p.location()
+	var shifted = tim.In(loc).Add(-p.RoundOffset)
+	var year, month, day = shifted.Date()
+	var start, end time.Time
+	if multipleUnits[op.Unit] {
+		var seconds = int64(units[op.Unit] / time.Second)
+		if int64(op.Amount) > (maxEpochSecond-minEpochSecond)/seconds {
+			return emptyTime, &RangeError{Op: op.String(), Reason: "bucket exceeds year range [-999999999, 999999999]"}
+		}
+		seconds *= int64(op.Amount)
+		// buckets are aligned on wall clock, so `/6h` starts at 00:00, 06:00, 12:00 and 18:00 of local time
+		var wall = time.Date(year, month, day, shifted.Hour(), shifted.Minute(), shifted.Second(), 0, time.UTC).Unix()
+		var floor = floorDiv(wall, seconds) * seconds
+		start, end = wallTime(floor, loc), wallTime(floor+seconds, loc)
+	} else {
+		var months = int64(op.Amount)
+		if op.Unit == "y" {
+			months *= 12
+		}
+		if months > (maxYear-minYear+1)*12 {
+			return emptyTime, &RangeError{Op: op.String(), Reason: "bucket exceeds year range [-999999999, 999999999]"}
+		}
+		var index = floorDiv(int64(year)*12+int64(month-1), months) * months
+		start = time.Date(int(floorDiv(index, 12)), time.Month(index-floorDiv(index, 12)*12+1), 1, 0, 0, 0, 0, loc)
+		end = time.Date(start.Year(), start.Month()+time.Month(months), 1, 0, 0, 0, 0, loc)
+	}
+	var res = start
+	if p.RoundUp {
+		res = end.Add(-time.Millisecond)
+	}
+	return res.Add(p.RoundOffset).UTC(), nil
+}
+
+// wallTime returns time in loc whose wall clock is sec seconds since epoch.
+func wallTime(sec int64, loc *time.Location) time.Time {
+	var u = time.Unix(sec, 0).UTC()
+	return time.Date(u.Year(), u.Month(), u.Day(), u.Hour(), u.Minute(), u.Second(), 0, loc)
+}
+
+// floorDiv returns a / b rounded toward negative infinity, b is positive.
+func floorDiv(a, b int64) int64 {
+	if a < 0 && a%b != 0 {
+		return a/b - 1
+	}
+	return a / b
+}
+
+// roundBusinessDay rounds tim down to start of the last business day, in round up mode
+// it rounds to the last millisecond of the business day instead.
+func (p *DateMathParser) roundBusinessDay(tim time.Time) (time.Time, error) {
+	var res, err = p.floorBusinessDay(tim)
+	if err != nil {
+		return emptyTime, err
+	}
+	if p.RoundUp {
+		res = p.nextUnit(res, "d").Add(-time.Millisecond)
+	}
+	return res.UTC(), nil
+}
+
+// roundPrecision fills fields missing from anchor literal with their max value, so `2021-05` covers
+// the whole month in round up mode. It ends at the last millisecond like roundUnit, as ElasticSearch
+// does, anchor of millisecond or finer precision is kept.
+func (p *DateMathParser) roundPrecision(tim time.Time, precision Precision) time.Time {
+	if !p.RoundUp {
+		return tim
+	}
+	if unit, ok := precisionUnits[precision]; ok {
+		var start = p.floorUnit(tim, unit)
+		if precision == PrecisionWeek {
+			// anchor is already the first day of week parsed by pattern, which may be ISO week
+			// of `xxxx-'W'ww` unlike the first day of week of parser
+			start = p.floorUnit(tim, "d")
+		}
+		return p.nextUnit(start, unit).Add(-time.Millisecond).UTC()
+	}
+	return tim
+}
@@ -0,0 +1,123 @@
+package datemath_parser
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// splunkTimeFormat is the default absolute time format of splunk `earliest` and `latest`.
+const splunkTimeFormat = "%m/%d/%Y:%H:%M:%S"
+
+// splunkOpRegexp matches offset like `-7d` and snap like `@w1`, longer unit names precede
+// their prefixes, so `mon` isn't read as minute.
+var splunkOpRegexp = regexp.MustCompile(`^(?:([\+-])(\d*)(seconds|second|secs|sec|months|month|mon|minutes|minute|mins|min|hours|hour|hrs|hr|days|day|weeks|week|quarters|quarter|qtrs|qtr|years|year|yrs|yr|s|m|h|d|w|q|y)|@(seconds|second|secs|sec|months|month|mon|minutes|minute|mins|min|hours|hour|hrs|hr|days|day|w[0-7]|weeks|week|quarters|quarter|qtrs|qtr|years|year|yrs|yr|s|m|h|d|w|q|y))`)
+
+// splunkUnits maps time unit of splunk to unit of MathOp.
+var splunkUnits = map[string]string{
+	"s": "s", "sec": "s", "secs": "s", "second": "s", "seconds": "s",
+	"m": "m", "min": "m", "mins": "m", "minute": "m", "minutes": "m",
+	"h": "h", "hr": "h", "hrs": "h", "hour": "h", "hours": "h",
+	"d": "d", "day": "d", "days": "d",
+	"w": "w", "week": "w", "weeks": "w",
+	"mon": "M", "month": "M", "months": "M",
+	"q": "Q", "qtr": "Q", "qtrs": "Q", "quarter": "Q", "quarters": "Q",
+	"y": "y", "yr": "y", "yrs": "y", "year": "y", "years": "y",
+}
+
+// parseSplunk parses time modifier of splunk like `-1mon@mon+2h`, which may be prefixed by `earliest=`
+// or `latest=`, and by `rt` of real-time search. Time without offset or snap is absolute time.
+func (p *DateMathParser) parseSplunk(expr string) (Result, error) {
+	expr = strings.TrimSpace(expr)
+	for _, prefix := range []string{"earliest=", "latest="} {
+		expr = strings.TrimPrefix(expr, prefix)
+	}
+	expr = strings.TrimPrefix(expr, "rt")
+	var res = Result{}
+	if expr == "" || expr == "now" || expr[0] == '+' || expr[0] == '-' || expr[0] == '@' {
+		res.Anchor = p.now().UTC()
+		res.IsNow = true
+		res.Precision = PrecisionNanosecond
+		var err error
+		if res.Ops, err = parseSplunkDur(strings.TrimPrefix(expr, "now")); err != nil {
+			return Result{Time: emptyTime, Anchor: emptyTime}, err
+		}
+	} else {
+		var err error
+		if res.Anchor, res.Format, res.Precision, err = p.parseSplunkAnchor(expr); err != nil {
+			return Result{Time: emptyTime, Anchor: emptyTime}, err
+		}
+		res.Anchor = res.Anchor.UTC()
+	}
+	// snap always rounds down, latest=@d is the exclusive end of yesterday
+	var floor = *p
+	floor.RoundUp = false
+	var err error
+	if res.Time, err = floor.applyOps(res.Ops, res.Anchor); err != nil {
+		return Result{Time: emptyTime, Anchor: emptyTime}, err
+	}
+	return res, nil
+}
+
+// parseSplunkAnchor parses absolute time of splunk, without formats it's epoch seconds like
+// `1620640800` or `1620640800.123`, or time like `05/10/2021:10:00:00`.
+func (p *DateMathParser) parseSplunkAnchor(expr string) (time.Time, string, Precision, error) {
+	if len(p.Formats) != 0 {
+		return p.parseAnchor(expr)
+	}
+	var sec, frac = expr, ""
+	if i := strings.Index(expr, "."); i != -1 {
+		sec, frac = expr[:i], expr[i+1:]
+	}
+	if s, err := strconv.ParseInt(sec, 10, 64); err == nil && sec[0] != '+' && sec[0] != '-' {
+		var nsec int64
+		if frac != "" {
+			if len(frac) > 9 || strings.Trim(frac, "0123456789") != "" {
+				return emptyTime, "", PrecisionUnknown, fmt.Errorf("epoch %s is invalid", expr)
+			}
+			nsec, _ = strconv.ParseInt(frac+strings.Repeat("0", 9-len(frac)), 10, 64)
+		}
+		var tim, err = epochTime(s, nsec)
+		return tim, EPOCH_SECOND, PrecisionSecond, err
+	}
+	var tim, err = time.ParseInLocation("01/02/2006:15:04:05", expr, p.location())
+	if err != nil {
+		return emptyTime, "", PrecisionUnknown, fmt.Errorf("time %s is invalid, expect epoch seconds or time like %s", expr, splunkTimeFormat)
+	}
+	return tim, splunkTimeFormat, PrecisionSecond, nil
+}
+
+// parseSplunkDur parses chained offsets and snaps like `-1mon@mon+2h` into operations, snap
+// is rounding of MathOp, `@w` and `@w7` snap to sunday like `@w0`.
+func parseSplunkDur(dur string) ([]MathOp, error) {
+	var ops []MathOp
+	for dur != "" {
+		var s = splunkOpRegexp.FindStringSubmatch(dur)
+		if s == nil {
+			return nil, fmt.Errorf("expect time modifier like -7d@d, got %s", dur)
+		}
+		dur = dur[len(s[0]):]
+		if s[4] != "" {
+			var unit = splunkUnits[s[4]]
+			if unit == "" {
+				unit = s[4] // w0 ~ w6
+			}
+			if unit == "w" || unit == "w7" {
+				unit = "w0"
+			}
+			ops = append(ops, MathOp{Op: '/', Unit: unit})
+			continue
+		}
+		var d = 1
+		if s[2] != "" {
+			var err error
+			if d, err = strconv.Atoi(s[2]); err != nil {
+				return nil, &RangeError{Op: s[0], Reason: "amount overflows int"}
+			}
+		}
+		ops = append(ops, MathOp{Op: s[1][0], Amount: d, Unit: splunkUnits[s[3]]})
+	}
+	return ops, nil
+}
@@ -0,0 +1,71 @@
+package datemath_parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateMathParser_splunk(t *testing.T) {
+	// 2021-05-12 is wednesday
+	var now = time.Date(2021, 5, 12, 10, 37, 42, 0, time.UTC)
+	tests := []struct {
+		name    string
+		opts    []DateMathParserOption
+		expr    string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "TestDateMathParser_splunk01", expr: "-7d@d", want: time.Date(2021, 5, 5, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_splunk02", expr: "@w1", want: time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_splunk03", expr: "@w0", want: time.Date(2021, 5, 9, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_splunk04", expr: "@w3", want: time.Date(2021, 5, 12, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_splunk05", expr: "@w4", want: time.Date(2021, 5, 6, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_splunk06", expr: "@w", want: time.Date(2021, 5, 9, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_splunk07", expr: "-1mon@mon+2h", want: time.Date(2021, 4, 1, 2, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_splunk08", expr: "rt-5m", want: time.Date(2021, 5, 12, 10, 32, 42, 0, time.UTC)},
+		{name: "TestDateMathParser_splunk09", expr: "earliest=-24h@h", want: time.Date(2021, 5, 11, 10, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_splunk10", expr: "latest=@d", opts: []DateMathParserOption{WithRoundUp(true)}, want: time.Date(2021, 5, 12, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_splunk11", expr: "-1q@q", want: time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_splunk12", expr: "-2years@y", want: time.Date(2019, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_splunk13", expr: "-30mins@min", want: time.Date(2021, 5, 12, 10, 7, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_splunk14", expr: "-h", want: time.Date(2021, 5, 12, 9, 37, 42, 0, time.UTC)},
+		{name: "TestDateMathParser_splunk15", expr: "now", want: now},
+		{name: "TestDateMathParser_splunk16", expr: "@d-2h", opts: []DateMathParserOption{WithTimeZone("+08:00")}, want: time.Date(2021, 5, 11, 14, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_splunk17", expr: "1620640800", want: time.Date(2021, 5, 10, 10, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_splunk18", expr: "1620640800.5", want: time.Date(2021, 5, 10, 10, 0, 0, 500000000, time.UTC)},
+		{name: "TestDateMathParser_splunk19", expr: "0", want: time.Unix(0, 0)},
+		{name: "TestDateMathParser_splunk20", expr: "05/10/2021:10:00:00", opts: []DateMathParserOption{WithTimeZone("+08:00")}, want: time.Date(2021, 5, 10, 2, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_splunk21", expr: "2021-05-10||", opts: []DateMathParserOption{WithFormat([]string{"date"})}, wantErr: true},
+		{name: "TestDateMathParser_splunk22", expr: "2021-05-10", opts: []DateMathParserOption{WithFormat([]string{"date"})}, want: time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_splunk23", expr: "-7x", wantErr: true},
+		{name: "TestDateMathParser_splunk24", expr: "@w8", wantErr: true},
+		{name: "TestDateMathParser_splunk25", expr: "-1w1", wantErr: true},
+		{name: "TestDateMathParser_splunk26", expr: "10/2021", wantErr: true},
+		{name: "TestDateMathParser_splunk27", expr: "@w7", want: time.Date(2021, 5, 9, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts = append([]DateMathParserOption{WithNow(func() time.Time { return now }), WithDialect(DialectSplunk)}, tt.opts...)
+			var p, err = NewDateMathParser(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got, perr = p.Parse(tt.expr)
+			if (perr != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", perr, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	var p, _ = NewDateMathParser(WithNow(func() time.Time { return now }), WithDialect(DialectSplunk))
+	var res, err = p.ParseDetailed("-1mon@mon+2h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res.Ops) != 3 || res.Ops[0] != (MathOp{Op: '-', Amount: 1, Unit: "M"}) || res.Ops[1] != (MathOp{Op: '/', Unit: "M"}) || res.Ops[2] != (MathOp{Op: '+', Amount: 2, Unit: "h"}) {
+		t.Errorf("ParseDetailed() ops = %v", res.Ops)
+	}
+}
@@ -0,0 +1,85 @@
+package datemath_parser
+
+import (
+	"fmt"
+)
+
+// strftimeDirectives maps directives of strftime to pattern elements, count is the width of value.
+var strftimeDirectives = map[rune]element{
+	'Y': {field: fieldYear, count: 4},
+	'y': {field: fieldYear, count: 2},
+	'G': {field: fieldWeekYear, count: 4},
+	'g': {field: fieldWeekYear, count: 2},
+	'V': {field: fieldWeek, count: 2},
+	'm': {field: fieldMonth, count: 2},
+	'b': {field: fieldMonth, count: 3, text: true},
+	'h': {field: fieldMonth, count: 3, text: true},
+	'B': {field: fieldMonth, count: 4, text: true},
+	'd': {field: fieldDay, count: 2},
+	'e': {field: fieldDay, count: 2, space: true},
+	'j': {field: fieldDayOfYear, count: 3},
+	'a': {field: fieldDayOfWeek, count: 3, text: true},
+	'A': {field: fieldDayOfWeek, count: 4, text: true},
+	'u': {field: fieldDayOfWeek, count: 1},
+	'p': {field: fieldHalfDay, count: 1},
+	'H': {field: fieldHourOfDay, count: 2},
+	'k': {field: fieldHourOfDay, count: 2, space: true},
+	'I': {field: fieldClockHourOfHalfDay, count: 2},
+	'l': {field: fieldClockHourOfHalfDay, count: 2, space: true},
+	'M': {field: fieldMinute, count: 2},
+	'S': {field: fieldSecond, count: 2},
+	'f': {field: fieldFraction, count: 6},
+	'z': {field: fieldOffset, count: 1, offset: offsetStyle{zulu: true, minutes: true, lenient: true}},
+	'Z': {field: fieldZoneName, count: 1},
+}
+
+// strftimeShortcuts are directives which are combination of other directives.
+var strftimeShortcuts = map[rune]string{
+	'F': "%Y-%m-%d",
+	'T': "%H:%M:%S",
+	'R': "%H:%M",
+	'D': "%m/%d/%y",
+	'r': "%I:%M:%S %p",
+}
+
+var strftimeLiterals = map[rune]string{
+	'%': "%",
+	'n': "\n",
+	't': "\t",
+}
+
+// compileStrftime compiles strftime pattern like `%Y-%m-%d %H:%M:%S` into elements,
+// `%f` is fraction of second like python, and `%z` accepts `Z`, `+HH`, `+HHMM` and `+HH:MM`.
+func compileStrftime(source string) ([]element, error) {
+	var elements = []element{}
+	var runes = []rune(source)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] != '%' {
+			elements = appendLiteral(elements, string(runes[i]))
+			continue
+		}
+		if i+1 == len(runes) {
+			return nil, fmt.Errorf("pattern: %s is invalid, %% is not followed by directive", source)
+		}
+		i++
+		var r = runes[i]
+		if e, ok := strftimeDirectives[r]; ok {
+			elements = append(elements, e)
+		} else if shortcut, ok := strftimeShortcuts[r]; ok {
+			var sub, _ = compileStrftime(shortcut)
+			for _, e := range sub {
+				if e.field == fieldLiteral {
+					elements = appendLiteral(elements, e.literal)
+				} else {
+					elements = append(elements, e)
+				}
+			}
+		} else if literal, ok := strftimeLiterals[r]; ok {
+			elements = appendLiteral(elements, literal)
+		} else {
+			return nil, fmt.Errorf("pattern: %s is invalid, directive %%%c is not supported", source, r)
+		}
+	}
+	markFixed(elements)
+	return elements, nil
+}
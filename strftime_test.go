@@ -0,0 +1,111 @@
+package datemath_parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestDateMathParser_strftime(t *testing.T) {
+	tests := []struct {
+		name          string
+		format        string
+		expr          string
+		want          time.Time
+		wantPrecision Precision
+		wantErr       bool
+	}{
+		{
+			name:          "TestDateMathParser_strftime01",
+			format:        "strftime:%Y-%m-%d %H:%M:%S",
+			expr:          "2021-05-10 10:20:30",
+			want:          time.Date(2021, 5, 10, 10, 20, 30, 0, time.UTC),
+			wantPrecision: PrecisionSecond,
+		},
+		{
+			name:          "TestDateMathParser_strftime02",
+			format:        "strftime:%Y%m%dT%H%M%S%z",
+			expr:          "20210510T102030+0800",
+			want:          time.Date(2021, 5, 10, 2, 20, 30, 0, time.UTC),
+			wantPrecision: PrecisionSecond,
+		},
+		{
+			name:          "TestDateMathParser_strftime03",
+			format:        "strftime:%FT%T.%f%z",
+			expr:          "2021-05-10T10:20:30.123Z",
+			want:          time.Date(2021, 5, 10, 10, 20, 30, 123000000, time.UTC),
+			wantPrecision: PrecisionMillisecond,
+		},
+		{
+			name:          "TestDateMathParser_strftime04",
+			format:        "strftime:%a %b %e %H:%M:%S %Y",
+			expr:          "Mon May  3 10:20:30 2021",
+			want:          time.Date(2021, 5, 3, 10, 20, 30, 0, time.UTC),
+			wantPrecision: PrecisionSecond,
+		},
+		{
+			name:          "TestDateMathParser_strftime05",
+			format:        "strftime:%d/%m/%y %I:%M %p",
+			expr:          "10/05/21 10:20 PM",
+			want:          time.Date(2021, 5, 10, 22, 20, 0, 0, time.UTC),
+			wantPrecision: PrecisionMinute,
+		},
+		{
+			name:          "TestDateMathParser_strftime06",
+			format:        "strftime:%G-W%V-%u",
+			expr:          "2021-W01-1",
+			want:          time.Date(2021, 1, 4, 0, 0, 0, 0, time.UTC),
+			wantPrecision: PrecisionDay,
+		},
+		{
+			name:          "TestDateMathParser_strftime07",
+			format:        "strftime:%Y-%j %%",
+			expr:          "2021-130 %",
+			want:          time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC),
+			wantPrecision: PrecisionDay,
+		},
+		{
+			name:    "TestDateMathParser_strftime08",
+			format:  "strftime:%Y-%m-%d",
+			expr:    "2021-02-30",
+			wantErr: true,
+		},
+		{
+			name:          "TestDateMathParser_strftime09",
+			format:        "strftime:%Y-%m-%d %H:%M:%S %Z",
+			expr:          "2021-05-10 10:20:30 PST",
+			want:          time.Date(2021, 5, 10, 18, 20, 30, 0, time.UTC),
+			wantPrecision: PrecisionSecond,
+		},
+		{
+			name:          "TestDateMathParser_strftime10",
+			format:        "strftime:%Y-%m-%d %H:%M:%S %Z",
+			expr:          "2021-05-10 10:20:30 CEST",
+			want:          time.Date(2021, 5, 10, 8, 20, 30, 0, time.UTC),
+			wantPrecision: PrecisionSecond,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p, err = NewDateMathParser(WithFormat([]string{tt.format}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var res, perr = p.ParseDetailed(tt.expr)
+			if (perr != nil) != tt.wantErr {
+				t.Errorf("ParseDetailed() error = %v, wantErr %v", perr, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && (!res.Time.Equal(tt.want) || res.Precision != tt.wantPrecision) {
+				t.Errorf("ParseDetailed() = %v %v, want %v %v", res.Time, res.Precision, tt.want, tt.wantPrecision)
+			}
+		})
+	}
+}
+
+func TestCompileStrftime(t *testing.T) {
+	for _, source := range []string{"%Y-%m-%", "%Y-%Q", "%s"} {
+		if _, err := compilePattern(source, PatternStrftime); err == nil {
+			t.Errorf("compilePattern(%q) should fail", source)
+		}
+	}
+}
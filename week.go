@@ -0,0 +1,74 @@
+package datemath_parser
+
+import (
+	"fmt"
+	"time"
+)
+
+// weekFields defines weeks of week based year, the first week is the earliest one
+// which has at least minDays days in the year.
+type weekFields struct {
+	start   time.Weekday
+	minDays int
+}
+
+// isoWeekFields is weeks of ISO 8601, which start on monday and the first week contains january 4th.
+var isoWeekFields = weekFields{start: time.Monday, minDays: 4}
+
+// weekFieldsOf returns weeks starting on start, weeks starting on monday are ISO 8601 weeks, and
+// others take the week containing january 1st as the first week like united states.
+func weekFieldsOf(start time.Weekday) weekFields {
+	if start == time.Monday {
+		return isoWeekFields
+	}
+	return weekFields{start: start, minDays: 1}
+}
+
+// firstWeek returns the first day of the first week of weekYear.
+func (w weekFields) firstWeek(weekYear int) time.Time {
+	var jan1 = time.Date(weekYear, time.January, 1, 0, 0, 0, 0, time.UTC)
+	var before = (int(jan1.Weekday()) - int(w.start) + 7) % 7
+	if 7-before >= w.minDays {
+		return jan1.AddDate(0, 0, -before)
+	}
+	return jan1.AddDate(0, 0, 7-before)
+}
+
+// weeksIn returns count of weeks of weekYear, which is 52 or 53.
+func (w weekFields) weeksIn(weekYear int) int {
+	return int(w.firstWeek(weekYear+1).Sub(w.firstWeek(weekYear)).Hours()) / (7 * 24)
+}
+
+// weekOf returns week based year and week of the date of tim.
+func (w weekFields) weekOf(tim time.Time) (int, int) {
+	var year, month, day = tim.Date()
+	var date = time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
+	var weekYear = year + 1
+	var first = w.firstWeek(weekYear)
+	for date.Before(first) {
+		weekYear--
+		first = w.firstWeek(weekYear)
+	}
+	return weekYear, int(date.Sub(first).Hours())/(7*24) + 1
+}
+
+// date returns date of week date, dayOfWeek is from monday (1) to sunday (7),
+// 0 means the first day of week.
+func (w weekFields) date(weekYear, week, dayOfWeek int) (time.Time, error) {
+	if dayOfWeek < 0 || dayOfWeek > 7 {
+		return emptyTime, fmt.Errorf("day of week %d is out of range [1, 7]", dayOfWeek)
+	}
+	if weeks := w.weeksIn(weekYear); week < 1 || week > weeks {
+		return emptyTime, fmt.Errorf("week %d is out of range [1, %d] of weekyear %d", week, weeks, weekYear)
+	}
+	var offset = 0
+	if dayOfWeek != 0 {
+		offset = (dayOfWeek%7 - int(w.start) + 7) % 7
+	}
+	return w.firstWeek(weekYear).AddDate(0, 0, (week-1)*7+offset), nil
+}
+
+// dayOfWeek returns day of week of tim numbered from the first day of week (1) to 7.
+func (w weekFields) dayOfWeek(tim time.Time) int {
+	return (int(tim.Weekday())-int(w.start)+7)%7 + 1
+}
@@ -0,0 +1,96 @@
+package datemath_parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestWeekFields(t *testing.T) {
+	for tim := time.Date(1999, 12, 1, 0, 0, 0, 0, time.UTC); tim.Year() < 2031; tim = tim.AddDate(0, 0, 1) {
+		var wantYear, wantWeek = tim.ISOWeek()
+		var year, week = isoWeekFields.weekOf(tim)
+		if year != wantYear || week != wantWeek {
+			t.Fatalf("weekOf(%v) = %d-%d, want %d-%d", tim, year, week, wantYear, wantWeek)
+		}
+		if got, err := isoWeekFields.date(year, week, isoWeekday(tim)); err != nil || !got.Equal(tim) {
+			t.Fatalf("date(%d, %d, %d) = %v, %v, want %v", year, week, isoWeekday(tim), got, err, tim)
+		}
+	}
+	var us = weekFieldsOf(time.Sunday)
+	tests := []struct {
+		name     string
+		tim      time.Time
+		wantYear int
+		wantWeek int
+	}{
+		{name: "TestWeekFields01", tim: time.Date(2020, 12, 26, 0, 0, 0, 0, time.UTC), wantYear: 2020, wantWeek: 52},
+		{name: "TestWeekFields02", tim: time.Date(2020, 12, 27, 0, 0, 0, 0, time.UTC), wantYear: 2021, wantWeek: 1},
+		{name: "TestWeekFields03", tim: time.Date(2021, 1, 2, 0, 0, 0, 0, time.UTC), wantYear: 2021, wantWeek: 1},
+		{name: "TestWeekFields04", tim: time.Date(2021, 1, 3, 0, 0, 0, 0, time.UTC), wantYear: 2021, wantWeek: 2},
+		{name: "TestWeekFields05", tim: time.Date(2022, 12, 31, 0, 0, 0, 0, time.UTC), wantYear: 2022, wantWeek: 53},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var year, week = us.weekOf(tt.tim)
+			if year != tt.wantYear || week != tt.wantWeek {
+				t.Errorf("weekOf() = %d-%d, want %d-%d", year, week, tt.wantYear, tt.wantWeek)
+			}
+			if got, err := us.date(year, week, isoWeekday(tt.tim)); err != nil || !got.Equal(tt.tim) {
+				t.Errorf("date() = %v, %v, want %v", got, err, tt.tim)
+			}
+		})
+	}
+}
+
+func TestDateMathParser_weekStart(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []DateMathParserOption
+		expr string
+		want time.Time
+	}{
+		{name: "TestDateMathParser_weekStart01", expr: "2021-05-12||/w", want: time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_weekStart02", opts: []DateMathParserOption{WithWeekStart(time.Sunday)}, expr: "2021-05-12||/w", want: time.Date(2021, 5, 9, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_weekStart03", opts: []DateMathParserOption{WithWeekStart(time.Sunday)}, expr: "2021-05-09||/w", want: time.Date(2021, 5, 9, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_weekStart04", opts: []DateMathParserOption{WithWeekStart(time.Sunday), WithRoundUp(true)}, expr: "2021-05-12||/w", want: time.Date(2021, 5, 15, 23, 59, 59, 999000000, time.UTC)},
+		{name: "TestDateMathParser_weekStart05", opts: []DateMathParserOption{WithWeekStart(time.Saturday)}, expr: "2021-05-12||/w", want: time.Date(2021, 5, 8, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_weekStart06", opts: []DateMathParserOption{WithLocale("en-US"), WithLocaleWeekStart()}, expr: "2021-05-12||/w", want: time.Date(2021, 5, 9, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_weekStart07", opts: []DateMathParserOption{WithLocaleWeekStart(), WithLocale("de-DE")}, expr: "2021-05-12||/w", want: time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_weekStart08", opts: []DateMathParserOption{WithWeekStart(time.Sunday), WithTimeZone("+08:00")}, expr: "2021-05-09T01:00:00+08:00||/w", want: time.Date(2021, 5, 8, 16, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_weekStart09", opts: []DateMathParserOption{WithFormat([]string{WEEKYEAR_WEEK}), WithWeekStart(time.Sunday), WithRoundUp(true)}, expr: "2021-W05", want: time.Date(2021, 2, 7, 23, 59, 59, 999000000, time.UTC)},
+		{name: "TestDateMathParser_weekStart10", opts: []DateMathParserOption{WithFormat([]string{WEEKYEAR_WEEK}), WithWeekStart(time.Sunday)}, expr: "2021-W05", want: time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_weekStart11", opts: []DateMathParserOption{WithLocale("en-US")}, expr: "2021-05-12||/w", want: time.Date(2021, 5, 9, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_weekStart12", opts: []DateMathParserOption{WithLocale("en-US"), WithWeekStart(time.Monday)}, expr: "2021-05-12||/w", want: time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_weekStart13", opts: []DateMathParserOption{WithWeekStart(time.Monday), WithLocale("en-US")}, expr: "2021-05-12||/w", want: time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts = append([]DateMathParserOption{WithFormat([]string{STRICT_DATE_OPTIONAL_TIME})}, tt.opts...)
+			var p, err = NewDateMathParser(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, err := p.Parse(tt.expr); err != nil || !got.Equal(tt.want) {
+				t.Errorf("Parse() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+	if _, err := NewDateMathParser(WithWeekStart(time.Weekday(7))); err == nil {
+		t.Errorf("expect error for invalid week start")
+	}
+}
+
+func TestDateMathParser_weekStartPattern(t *testing.T) {
+	var p, _ = NewDateMathParser(WithWeekStart(time.Sunday), WithLocale("de-DE"))
+	var tim = time.Date(2020, 12, 31, 0, 0, 0, 0, time.UTC)
+	if got, err := p.Format(tim, "java:YYYY-'W'ww-e"); err != nil || got != "2021-W01-5" {
+		t.Errorf("Format() = %s, %v", got, err)
+	}
+	if got, err := p.Format(tim, "xxxx-'W'ww-e"); err != nil || got != "2020-W53-4" {
+		t.Errorf("Format() = %s, %v", got, err)
+	}
+	p, _ = NewDateMathParser(WithWeekStart(time.Sunday), WithFormat([]string{"java:YYYY-'W'ww-e"}))
+	if got, err := p.Parse("2021-W01-1"); err != nil || !got.Equal(time.Date(2020, 12, 27, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Parse() = %v, %v", got, err)
+	}
+}
@@ -0,0 +1,113 @@
+package datemath_parser
+
+import (
+	"fmt"
+	"time"
+)
+
+// YearInference is the strategy to decide year of value parsed by pattern without year,
+// like `MMM dd HH:mm:ss` of syslog, the year is decided by now of parser.
+type YearInference int
+
+const (
+	// YearInferenceClosest takes the year which puts the result closest to now,
+	// and not later than now by more than one day.
+	YearInferenceClosest YearInference = iota
+	// YearInferencePast takes the latest year which puts the result not later than now.
+	YearInferencePast
+	// YearInferenceCurrent takes the year of now.
+	YearInferenceCurrent
+	// YearInferenceNone keeps year 0 like patterns without any date field.
+	YearInferenceNone
+)
+
+func (y YearInference) String() string {
+	switch y {
+	case YearInferenceClosest:
+		return "closest"
+	case YearInferencePast:
+		return "past"
+	case YearInferenceCurrent:
+		return "current"
+	case YearInferenceNone:
+		return "none"
+	}
+	return fmt.Sprintf("YearInference(%d)", int(y))
+}
+
+// yearInferenceFuture is how far the time inferred by YearInferenceClosest can be later than now.
+const yearInferenceFuture = 24 * time.Hour
+
+// yearInferenceRange is how many years before now are tried, february 29 may be 8 years ago.
+const yearInferenceRange = 8
+
+// needsYear reports whether month is parsed without any year field, like `MMM dd HH:mm:ss` of syslog.
+func (r *parsed) needsYear() bool {
+	for _, f := range []field{fieldYear, fieldYearOfEra, fieldWeekYear} {
+		if _, ok := r.values[f]; ok {
+			return false
+		}
+	}
+	var _, ok = r.values[fieldMonth]
+	return ok
+}
+
+// inferYear resolves fields with the year decided by strategy, candidates are the next
+// year of now and yearInferenceRange years before, which skips invalid date like february 29.
+func (r *parsed) inferYear(loc *time.Location, now time.Time, strategy YearInference) (time.Time, error) {
+	var year = now.In(loc).Year()
+	if strategy == YearInferenceCurrent {
+		return r.withYear(year).resolve(loc)
+	}
+	var future = yearInferenceFuture
+	if strategy == YearInferencePast {
+		future = 0
+	}
+	var best, found, lastErr = emptyTime, false, error(nil)
+	for y := year + 1; y >= year-yearInferenceRange; y-- {
+		var tim, err = r.withYear(y).resolve(loc)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if tim.Sub(now) > future {
+			continue
+		}
+		if !found || absDuration(tim.Sub(now)) < absDuration(best.Sub(now)) {
+			best, found = tim, true
+		}
+	}
+	if !found {
+		return emptyTime, lastErr
+	}
+	return best, nil
+}
+
+func (r *parsed) withYear(year int) *parsed {
+	var res = r.clone()
+	res.values[fieldYear] = year
+	return res
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
+
+// twoDigitYear expands two digit year v into [pivot, pivot+99]. Without pivot java maps
+// it into 2000~2099, and others keep the pivot of go time layout 06, which maps 69~99 into 1969~1999.
+func twoDigitYear(v int, dialect PatternDialect, pivot int) int {
+	if pivot > 0 {
+		var year = pivot - pivot%100 + v
+		if year < pivot {
+			year += 100
+		}
+		return year
+	}
+	if dialect == PatternJava || v < 69 {
+		return 2000 + v
+	}
+	return 1900 + v
+}
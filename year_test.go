@@ -0,0 +1,273 @@
+package datemath_parser
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPattern_inferYear(t *testing.T) {
+	tests := []struct {
+		name    string
+		now     time.Time
+		expr    string
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name: "TestPattern_inferYear01",
+			now:  time.Date(2021, 5, 10, 12, 0, 0, 0, time.UTC),
+			expr: "May 10 10:20:30",
+			want: time.Date(2021, 5, 10, 10, 20, 30, 0, time.UTC),
+		},
+		{
+			name: "TestPattern_inferYear02",
+			now:  time.Date(2021, 1, 1, 0, 5, 0, 0, time.UTC),
+			expr: "Dec 31 23:59:59",
+			want: time.Date(2020, 12, 31, 23, 59, 59, 0, time.UTC),
+		},
+		{
+			name: "TestPattern_inferYear03",
+			now:  time.Date(2020, 12, 31, 23, 50, 0, 0, time.UTC),
+			expr: "Jan  1 00:05:00",
+			want: time.Date(2021, 1, 1, 0, 5, 0, 0, time.UTC),
+		},
+		{
+			name: "TestPattern_inferYear04",
+			now:  time.Date(2021, 5, 10, 12, 0, 0, 0, time.UTC),
+			expr: "May 20 10:20:30",
+			want: time.Date(2020, 5, 20, 10, 20, 30, 0, time.UTC),
+		},
+		{
+			name: "TestPattern_inferYear05",
+			now:  time.Date(2021, 3, 1, 0, 0, 0, 0, time.UTC),
+			expr: "Feb 29 10:20:30",
+			want: time.Date(2020, 2, 29, 10, 20, 30, 0, time.UTC),
+		},
+		{
+			name: "TestPattern_inferYear06",
+			now:  time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC),
+			expr: "Feb 29 10:20:30",
+			want: time.Date(2020, 2, 29, 10, 20, 30, 0, time.UTC),
+		},
+		{
+			name:    "TestPattern_inferYear07",
+			now:     time.Date(2022, 3, 1, 0, 0, 0, 0, time.UTC),
+			expr:    "Feb 30 10:20:30",
+			wantErr: true,
+		},
+	}
+	var pat, err = compilePattern("%b %e %H:%M:%S", PatternStrftime)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got, _, err = pat.parse(tt.expr, &parseContext{loc: time.UTC, locale: englishLocale, now: tt.now})
+			if (err != nil) != tt.wantErr {
+				t.Errorf("parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDateMathParser_yearInference(t *testing.T) {
+	var now = time.Date(2021, 1, 1, 0, 5, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		strategy YearInference
+		expr     string
+		want     time.Time
+		wantErr  bool
+	}{
+		{
+			name:     "TestDateMathParser_yearInference01",
+			strategy: YearInferenceClosest,
+			expr:     "Jan  1 08:00:00",
+			want:     time.Date(2021, 1, 1, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "TestDateMathParser_yearInference02",
+			strategy: YearInferencePast,
+			expr:     "Jan  1 08:00:00",
+			want:     time.Date(2020, 1, 1, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "TestDateMathParser_yearInference03",
+			strategy: YearInferenceCurrent,
+			expr:     "Dec 31 23:59:59",
+			want:     time.Date(2021, 12, 31, 23, 59, 59, 0, time.UTC),
+		},
+		{
+			name:     "TestDateMathParser_yearInference04",
+			strategy: YearInferenceNone,
+			expr:     "Dec 31 23:59:59",
+			want:     time.Date(0, 12, 31, 23, 59, 59, 0, time.UTC),
+		},
+		{
+			name:     "TestDateMathParser_yearInference05",
+			strategy: YearInferenceCurrent,
+			expr:     "Feb 29 10:00:00",
+			wantErr:  true,
+		},
+		{
+			name:     "TestDateMathParser_yearInference06",
+			strategy: YearInferencePast,
+			expr:     "Feb 29 10:00:00",
+			want:     time.Date(2020, 2, 29, 10, 0, 0, 0, time.UTC),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p, err = NewDateMathParser(
+				WithFormat([]string{RFC3164}),
+				WithYearInference(tt.strategy),
+				WithNow(func() time.Time { return now }),
+			)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got, perr = p.Parse(tt.expr)
+			if (perr != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", perr, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if _, err := NewDateMathParser(WithYearInference(YearInference(10))); err == nil {
+		t.Errorf("expect error for invalid year inference")
+	}
+}
+
+func TestDateMathParser_now(t *testing.T) {
+	var now = time.Date(2021, 5, 10, 10, 20, 30, 0, time.UTC)
+	var p, _ = NewDateMathParser(WithNow(func() time.Time { return now }))
+	if got, err := p.Parse("now-1d/d"); err != nil || !got.Equal(time.Date(2021, 5, 9, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Parse() = %v, %v", got, err)
+	}
+}
+
+func TestDateMathParser_twoDigitYear(t *testing.T) {
+	var now = time.Date(2021, 5, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name   string
+		opts   []DateMathParserOption
+		format string
+		expr   string
+		want   int
+	}{
+		{name: "TestDateMathParser_twoDigitYear01", format: "dd/MM/yy", expr: "10/05/68", want: 2068},
+		{name: "TestDateMathParser_twoDigitYear02", format: "dd/MM/yy", expr: "10/05/69", want: 1969},
+		{name: "TestDateMathParser_twoDigitYear03", format: "java:dd/MM/yy", expr: "10/05/69", want: 2069},
+		{name: "TestDateMathParser_twoDigitYear04", format: "strftime:%d/%m/%y", expr: "10/05/69", want: 1969},
+		{name: "TestDateMathParser_twoDigitYear05", opts: []DateMathParserOption{WithTwoDigitYearPivot(1950)}, format: "dd/MM/yy", expr: "10/05/50", want: 1950},
+		{name: "TestDateMathParser_twoDigitYear06", opts: []DateMathParserOption{WithTwoDigitYearPivot(1950)}, format: "java:dd/MM/yy", expr: "10/05/49", want: 2049},
+		{name: "TestDateMathParser_twoDigitYear07", opts: []DateMathParserOption{WithTwoDigitYearPivot(1950)}, format: "strftime:%d/%m/%y", expr: "10/05/99", want: 1999},
+		{name: "TestDateMathParser_twoDigitYear08", opts: []DateMathParserOption{WithTwoDigitYearPivot(1900)}, format: "dd/MM/yy", expr: "10/05/21", want: 1921},
+		{name: "TestDateMathParser_twoDigitYear09", opts: []DateMathParserOption{WithTwoDigitYearWindow(80)}, format: "dd/MM/yy", expr: "10/05/41", want: 1941},
+		{name: "TestDateMathParser_twoDigitYear10", opts: []DateMathParserOption{WithTwoDigitYearWindow(80)}, format: "java:dd/MM/yy", expr: "10/05/40", want: 2040},
+		{name: "TestDateMathParser_twoDigitYear11", opts: []DateMathParserOption{WithTwoDigitYearWindow(80)}, format: "xx-'W'ww-e", expr: "40-W01-1", want: 2040},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var opts = append([]DateMathParserOption{WithFormat([]string{tt.format}), WithNow(func() time.Time { return now })}, tt.opts...)
+			var p, err = NewDateMathParser(opts...)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, err := p.Parse(tt.expr); err != nil || got.Year() != tt.want {
+				t.Errorf("Parse() = %v, %v, want year %d", got, err, tt.want)
+			}
+		})
+	}
+	for _, opt := range []DateMathParserOption{WithTwoDigitYearPivot(0), WithTwoDigitYearWindow(100), WithTwoDigitYearWindow(-1)} {
+		if _, err := NewDateMathParser(opt); err == nil {
+			t.Errorf("expect error for invalid two digit year option")
+		}
+	}
+}
+
+func TestDateMathParser_extendedYear(t *testing.T) {
+	tests := []struct {
+		name    string
+		formats []string
+		expr    string
+		want    time.Time
+		wantErr bool
+	}{
+		{name: "TestDateMathParser_extendedYear01", formats: []string{STRICT_DATE_OPTIONAL_TIME}, expr: "+10000-01-01", want: time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_extendedYear02", formats: []string{STRICT_DATE_OPTIONAL_TIME}, expr: "-0044-03-15T12:00:00Z", want: time.Date(-44, 3, 15, 12, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_extendedYear03", formats: []string{DATE}, expr: "1492-10-12", want: time.Date(1492, 10, 12, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_extendedYear04", formats: []string{"java:uuuu-MM-dd"}, expr: "+10000-01-01", want: time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_extendedYear05", formats: []string{"java:uuuu-MM-dd"}, expr: "-0044-03-15", want: time.Date(-44, 3, 15, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_extendedYear06", formats: []string{"java:uuuu-MM-dd"}, expr: "044-03-15", wantErr: true},
+		{name: "TestDateMathParser_extendedYear07", formats: []string{DATE}, expr: "+999999999-12-31", want: time.Date(999999999, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_extendedYear08", formats: []string{DATE}, expr: "+1000000000-01-01", wantErr: true},
+		{name: "TestDateMathParser_extendedYear09", formats: []string{BASIC_DATE}, expr: "+20210510", wantErr: true},
+		{name: "TestDateMathParser_extendedYear10", formats: []string{EPOCH_SECOND}, expr: "-86400", want: time.Date(1969, 12, 31, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_extendedYear11", formats: []string{EPOCH_MILLIS}, expr: "-1500", want: time.Date(1969, 12, 31, 23, 59, 58, 500000000, time.UTC)},
+		{name: "TestDateMathParser_extendedYear12", formats: []string{EPOCH_MILLIS}, expr: "-14831769600000", want: time.Date(1500, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_extendedYear13", formats: []string{EPOCH_SECOND}, expr: "253402300800", want: time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_extendedYear14", formats: []string{EPOCH_MILLIS, EPOCH_SECOND}, expr: "1640138940000", want: time.Date(2021, 12, 22, 2, 9, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_extendedYear15", formats: []string{EPOCH_SECOND}, expr: "99999999999999999", wantErr: true},
+		{name: "TestDateMathParser_extendedYear16", formats: []string{DATE}, expr: "1492-10-12||+1d/d", want: time.Date(1492, 10, 13, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_extendedYear17", formats: []string{DATE}, expr: "2021-01-01||-500y", want: time.Date(2021, 1, 1-500*365, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_extendedYear18", formats: []string{DATE}, expr: "-0044-03-15||/y", want: time.Date(-44, 1, 1, 0, 0, 0, 0, time.UTC)},
+		// the first listed epoch format wins
+		{name: "TestDateMathParser_extendedYear19", formats: []string{EPOCH_SECOND, EPOCH_MILLIS}, expr: "-62135596800", want: time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_extendedYear20", formats: []string{EPOCH_SECOND}, expr: "-62135596800", want: time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_extendedYear21", formats: []string{EPOCH_AUTO}, expr: "-62135596800", want: time.Date(1, 1, 1, 0, 0, 0, 0, time.UTC)},
+		{name: "TestDateMathParser_extendedYear22", formats: []string{EPOCH_MILLIS, EPOCH_SECOND}, expr: "-62135596800", want: time.Date(1968, 1, 12, 20, 6, 43, 200000000, time.UTC)},
+		{name: "TestDateMathParser_extendedYear23", formats: []string{EPOCH_SECOND, EPOCH_MILLIS}, expr: "1640138940000", want: time.Date(53943, 12, 8, 14, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var p, err = NewDateMathParser(WithFormat(tt.formats))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var got, perr = p.Parse(tt.expr)
+			if (perr != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", perr, tt.wantErr)
+				return
+			}
+			if !tt.wantErr && !got.Equal(tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDateMathParser_formatExtendedYear(t *testing.T) {
+	tests := []struct {
+		name   string
+		tim    time.Time
+		format string
+		want   string
+	}{
+		{name: "TestDateMathParser_formatExtendedYear01", tim: time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC), format: DATE, want: "+10000-01-01"},
+		{name: "TestDateMathParser_formatExtendedYear02", tim: time.Date(-44, 3, 15, 0, 0, 0, 0, time.UTC), format: DATE, want: "-0044-03-15"},
+		{name: "TestDateMathParser_formatExtendedYear03", tim: time.Date(5, 3, 15, 0, 0, 0, 0, time.UTC), format: "java:uuuu-MM-dd", want: "0005-03-15"},
+		{name: "TestDateMathParser_formatExtendedYear04", tim: time.Date(-44, 3, 15, 0, 0, 0, 0, time.UTC), format: "java:yyyy", want: "0045"},
+		{name: "TestDateMathParser_formatExtendedYear05", tim: time.Date(1500, 1, 1, 0, 0, 0, 0, time.UTC), format: EPOCH_MILLIS, want: "-14831769600000"},
+		{name: "TestDateMathParser_formatExtendedYear06", tim: time.Date(10000, 1, 1, 0, 0, 0, 0, time.UTC), format: EPOCH_SECOND, want: "253402300800"},
+		{name: "TestDateMathParser_formatExtendedYear07", tim: time.Date(1969, 12, 31, 23, 59, 58, 500000000, time.UTC), format: EPOCH_MILLIS, want: "-1500"},
+	}
+	var p, _ = NewDateMathParser()
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := p.Format(tt.tim, tt.format); err != nil || got != tt.want {
+				t.Errorf("Format() = %q, %v, want %q", got, err, tt.want)
+			}
+		})
+	}
+	if _, err := p.Format(time.Date(999999999, 1, 1, 0, 0, 0, 0, time.UTC), EPOCH_MILLIS); err == nil {
+		t.Errorf("expect error for epoch_millis out of range")
+	}
+}